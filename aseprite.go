@@ -0,0 +1,742 @@
+package sprite
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+	"math"
+	"os"
+	"sort"
+)
+
+var (
+	ErrInvalidAseprite = errors.New("invalid aseprite file")
+)
+
+const (
+	aseFileMagic  = 0xA5E0
+	aseFrameMagic = 0xF1FA
+
+	aseChunkOldPalette = 0x0004
+	aseChunkLayer      = 0x2004
+	aseChunkCel        = 0x2005
+	aseChunkCelExtra   = 0x2006
+	aseChunkTags       = 0x2018
+	aseChunkPalette    = 0x2019
+	aseChunkUserData   = 0x2020
+	aseChunkSlice      = 0x2022
+
+	aseLayerVisible    = 1
+	aseLayerBackground = 8
+	aseLayerReference  = 64
+
+	aseLayerGroup = 1
+
+	aseCelRaw        = 0
+	aseCelLinked     = 1
+	aseCelCompressed = 2
+
+	aseUserDataText  = 1
+	aseUserDataColor = 2
+
+	aseSliceNineSlice = 1
+	aseSlicePivot     = 2
+)
+
+// aseDirections maps the loop direction stored in a tags chunk to a Direction.
+var aseDirections = []Direction{PlayForward, PlayBackward, PlayPingPong, PlayPingPongReverse}
+
+// OpenAsepriteBinary will use os.Open() to read the .aseprite / .ase file path specified. It returns a *File, along with an
+// atlas image containing every frame already composited. Files created with OpenAsepriteBinary() will put the filepath used
+// in the Path field.
+func OpenAsepriteBinary(path string) (*File, *image.RGBA, error) {
+	r, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer r.Close()
+
+	f, img, err := ReadAsepriteBinary(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f.Path = path
+	return f, img, nil
+}
+
+// ReadAsepriteBinary returns a *File and the composited atlas image for a given .aseprite / .ase file. The frames are laid out
+// in a grid on the atlas; the File's Frames point to their position in it, just like with a JSON export.
+func ReadAsepriteBinary(r io.Reader) (*File, *image.RGBA, error) {
+	d := &asepriteDecoder{file: &File{}}
+	if err := d.decode(r); err != nil {
+		return nil, nil, err
+	}
+
+	return d.file, d.atlas(), nil
+}
+
+type aseHeader struct {
+	FileSize         uint32
+	Magic            uint16
+	Frames           uint16
+	Width, Height    uint16
+	Depth            uint16
+	Flags            uint32
+	Speed            uint16
+	_                [2]uint32
+	TransparentIndex uint8
+	_                [3]uint8
+	NumColors        uint16
+	PixelW, PixelH   uint8
+	GridX, GridY     int16
+	GridW, GridH     uint16
+	_                [84]uint8
+}
+
+type aseFrameHeader struct {
+	Size      uint32
+	Magic     uint16
+	OldChunks uint16
+	Duration  uint16
+	_         [2]uint8
+	Chunks    uint32
+}
+
+type aseLayer struct {
+	flags      uint16
+	kind       uint16
+	childLevel uint16
+	opacity    uint8
+	blendMode  BlendMode
+	visible    bool
+}
+
+type aseCel struct {
+	layer   int
+	x, y    int
+	opacity uint8
+	z       int
+	img     *image.NRGBA
+}
+
+type asepriteDecoder struct {
+	header     aseHeader
+	file       *File
+	palette    []color.NRGBA
+	newPalette bool
+	layers     []aseLayer
+	cels       [][]*aseCel
+
+	// userData receives the next user data chunk, as it applies to the chunk read just before it. hasColor is false if
+	// the chunk has no color, which then keeps the one the chunk before it has.
+	userData func(text string, color int64, hasColor bool)
+}
+
+func (d *asepriteDecoder) decode(r io.Reader) error {
+	if err := binary.Read(r, binary.LittleEndian, &d.header); err != nil {
+		return err
+	}
+
+	if d.header.Magic != aseFileMagic {
+		return ErrInvalidAseprite
+	}
+
+	switch d.header.Depth {
+	case 32, 16, 8:
+	default:
+		return fmt.Errorf("%w: unsupported color depth %d", ErrInvalidAseprite, d.header.Depth)
+	}
+
+	d.palette = make([]color.NRGBA, 256)
+	d.cels = make([][]*aseCel, d.header.Frames)
+
+	f := d.file
+	f.Tags = make(map[string]*Tag)
+	f.FrameWidth = int32(d.header.Width)
+	f.FrameHeight = int32(d.header.Height)
+
+	for i := range d.cels {
+		fh := aseFrameHeader{}
+		if err := binary.Read(r, binary.LittleEndian, &fh); err != nil {
+			return err
+		}
+
+		if fh.Magic != aseFrameMagic {
+			return ErrInvalidAseprite
+		}
+
+		f.Frames = append(f.Frames, Frame{
+			W:        int(d.header.Width),
+			H:        int(d.header.Height),
+			Duration: float32(fh.Duration) / 1000,
+			SourceW:  int(d.header.Width),
+			SourceH:  int(d.header.Height),
+		})
+
+		chunks := int(fh.Chunks)
+		if chunks == 0 {
+			chunks = int(fh.OldChunks)
+		}
+
+		for c := 0; c < chunks; c++ {
+			if err := d.readChunk(r, i); err != nil {
+				return err
+			}
+		}
+	}
+
+	f.Tags[""] = &Tag{
+		Name:      "",
+		Start:     0,
+		End:       len(f.Frames) - 1,
+		Direction: PlayForward,
+		File:      f,
+	}
+
+	f.collectEvents()
+	return nil
+}
+
+func (d *asepriteDecoder) readChunk(r io.Reader, frame int) error {
+	var size uint32
+	var kind uint16
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return err
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &kind); err != nil {
+		return err
+	}
+
+	if size < 6 {
+		return ErrInvalidAseprite
+	}
+
+	data := make([]byte, size-6)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return err
+	}
+
+	// User data chunks apply to the chunk before them, if it's one that can have user data. The extra data of a cel goes
+	// between the cel and its user data.
+	if kind != aseChunkUserData && kind != aseChunkCelExtra {
+		d.userData = nil
+	}
+
+	cr := bytes.NewReader(data)
+	switch kind {
+	case aseChunkOldPalette:
+		return d.readOldPalette(cr)
+	case aseChunkPalette:
+		return d.readPalette(cr)
+	case aseChunkLayer:
+		return d.readLayer(cr)
+	case aseChunkCel:
+		return d.readCel(cr, frame)
+	case aseChunkTags:
+		return d.readTags(cr)
+	case aseChunkSlice:
+		return d.readSlice(cr)
+	case aseChunkUserData:
+		return d.readUserData(cr)
+	}
+
+	return nil
+}
+
+func (d *asepriteDecoder) readOldPalette(r *bytes.Reader) error {
+	var packets uint16
+	if err := binary.Read(r, binary.LittleEndian, &packets); err != nil {
+		return err
+	}
+
+	index := 0
+	for p := 0; p < int(packets); p++ {
+		var skip, count uint8
+		if err := binary.Read(r, binary.LittleEndian, &skip); err != nil {
+			return err
+		}
+		if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+			return err
+		}
+
+		index += int(skip)
+		n := int(count)
+		if n == 0 {
+			n = 256
+		}
+
+		for c := 0; c < n; c++ {
+			rgb := [3]uint8{}
+			if err := binary.Read(r, binary.LittleEndian, &rgb); err != nil {
+				return err
+			}
+
+			// The new palette chunk takes precedence, as it also carries alpha.
+			if !d.newPalette && index < len(d.palette) {
+				d.palette[index] = color.NRGBA{rgb[0], rgb[1], rgb[2], 255}
+			}
+
+			index++
+		}
+	}
+
+	return nil
+}
+
+func (d *asepriteDecoder) readPalette(r *bytes.Reader) error {
+	var header struct {
+		Size, First, Last uint32
+		_                 [8]uint8
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+
+	if int(header.Size) > len(d.palette) {
+		d.palette = append(d.palette, make([]color.NRGBA, int(header.Size)-len(d.palette))...)
+	}
+
+	d.newPalette = true
+	for i := header.First; i <= header.Last && int(i) < len(d.palette); i++ {
+		var entry struct {
+			Flags      uint16
+			R, G, B, A uint8
+		}
+
+		if err := binary.Read(r, binary.LittleEndian, &entry); err != nil {
+			return err
+		}
+
+		if entry.Flags&1 != 0 {
+			readAseString(r)
+		}
+
+		d.palette[i] = color.NRGBA{entry.R, entry.G, entry.B, entry.A}
+	}
+
+	return nil
+}
+
+func (d *asepriteDecoder) readLayer(r *bytes.Reader) error {
+	var header struct {
+		Flags, Type, ChildLevel uint16
+		DefaultW, DefaultH      uint16
+		BlendMode               uint16
+		Opacity                 uint8
+		_                       [3]uint8
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+
+	name := readAseString(r)
+
+	opacity := header.Opacity
+	if d.header.Flags&1 == 0 {
+		opacity = 255
+	}
+
+	blendMode := BlendMode(header.BlendMode)
+	if int(blendMode) >= len(blendModeNames) {
+		blendMode = BlendNormal
+	}
+
+	layer := aseLayer{
+		flags:      header.Flags,
+		kind:       header.Type,
+		childLevel: header.ChildLevel,
+		opacity:    opacity,
+		blendMode:  blendMode,
+		visible:    header.Flags&aseLayerVisible != 0 && header.Flags&aseLayerReference == 0,
+	}
+
+	// A layer nested in a hidden group is hidden as well.
+	for i := len(d.layers) - 1; i >= 0; i-- {
+		if d.layers[i].childLevel < layer.childLevel {
+			layer.visible = layer.visible && d.layers[i].visible
+			break
+		}
+	}
+
+	d.layers = append(d.layers, layer)
+	d.file.Layers = append(d.file.Layers, Layer{Name: name, Opacity: opacity, BlendMode: blendMode})
+
+	index := len(d.file.Layers) - 1
+	d.userData = func(text string, color int64, hasColor bool) {
+		d.file.Layers[index].Data = UserData(text)
+		if hasColor {
+			d.file.Layers[index].Color = color
+		}
+	}
+
+	return nil
+}
+
+func (d *asepriteDecoder) readCel(r *bytes.Reader, frame int) error {
+	var header struct {
+		Layer   uint16
+		X, Y    int16
+		Opacity uint8
+		Type    uint16
+		Z       int16
+		_       [5]uint8
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+
+	cel := &aseCel{
+		layer:   int(header.Layer),
+		x:       int(header.X),
+		y:       int(header.Y),
+		opacity: header.Opacity,
+		z:       int(header.Z),
+	}
+
+	d.userData = func(text string, color int64, hasColor bool) {
+		if cel.layer < len(d.file.Layers) {
+			layer := &d.file.Layers[cel.layer]
+			layer.Cels = append(layer.Cels, Cel{Frame: frame, Data: UserData(text), Color: color})
+		}
+	}
+
+	switch header.Type {
+	case aseCelLinked:
+		var linked uint16
+		if err := binary.Read(r, binary.LittleEndian, &linked); err != nil {
+			return err
+		}
+
+		if int(linked) >= len(d.cels) {
+			return ErrInvalidAseprite
+		}
+
+		for _, c := range d.cels[linked] {
+			if c.layer == cel.layer {
+				cel.img = c.img
+			}
+		}
+
+	case aseCelRaw, aseCelCompressed:
+		var size struct{ W, H uint16 }
+		if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+			return err
+		}
+
+		var pixels io.Reader = r
+		if header.Type == aseCelCompressed {
+			zr, err := zlib.NewReader(r)
+			if err != nil {
+				return err
+			}
+
+			defer zr.Close()
+			pixels = zr
+		}
+
+		img, err := d.readPixels(pixels, int(size.W), int(size.H), cel.layer)
+		if err != nil {
+			return err
+		}
+
+		cel.img = img
+
+	default:
+		// Tilemap cels are not supported.
+		return nil
+	}
+
+	if cel.img != nil {
+		d.cels[frame] = append(d.cels[frame], cel)
+	}
+
+	return nil
+}
+
+// readPixels reads w*h pixels in the color depth of the file and converts them into a non-premultiplied image.
+func (d *asepriteDecoder) readPixels(r io.Reader, w, h, layer int) (*image.NRGBA, error) {
+	bpp := int(d.header.Depth) / 8
+	buf := make([]byte, w*h*bpp)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+
+	background := layer < len(d.layers) && d.layers[layer].flags&aseLayerBackground != 0
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for i := 0; i < w*h; i++ {
+		var c color.NRGBA
+		switch bpp {
+		case 4:
+			c = color.NRGBA{buf[i*4], buf[i*4+1], buf[i*4+2], buf[i*4+3]}
+		case 2:
+			c = color.NRGBA{buf[i*2], buf[i*2], buf[i*2], buf[i*2+1]}
+		case 1:
+			index := buf[i]
+			if index != d.header.TransparentIndex || background {
+				c = d.palette[index]
+			}
+		}
+
+		copy(img.Pix[i*4:], []byte{c.R, c.G, c.B, c.A})
+	}
+
+	return img, nil
+}
+
+func (d *asepriteDecoder) readTags(r *bytes.Reader) error {
+	var header struct {
+		Count uint16
+		_     [8]uint8
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+
+	f := d.file
+	tags := make([]*Tag, 0, header.Count)
+	for i := 0; i < int(header.Count); i++ {
+		var tag struct {
+			From, To  uint16
+			Direction uint8
+			Repeat    uint16
+			_         [6]uint8
+			Color     [3]uint8
+			_         uint8
+		}
+
+		if err := binary.Read(r, binary.LittleEndian, &tag); err != nil {
+			return err
+		}
+
+		name := readAseString(r)
+
+		direction := PlayForward
+		if int(tag.Direction) < len(aseDirections) {
+			direction = aseDirections[tag.Direction]
+		}
+
+		f.Tags[name] = &Tag{
+			Name:      name,
+			Start:     int(tag.From),
+			End:       int(tag.To),
+			Direction: direction,
+			Repeat:    int(tag.Repeat),
+			Color:     int64(tag.Color[0])<<24 | int64(tag.Color[1])<<16 | int64(tag.Color[2])<<8 | 0xff,
+			File:      f,
+		}
+
+		tags = append(tags, f.Tags[name])
+	}
+
+	// The tags chunk is followed by a user data chunk for each tag, in order.
+	d.userData = func(text string, color int64, hasColor bool) {
+		if len(tags) == 0 {
+			return
+		}
+
+		tags[0].Data = UserData(text)
+		if hasColor {
+			tags[0].Color = color
+		}
+		tags = tags[1:]
+	}
+
+	return nil
+}
+
+func (d *asepriteDecoder) readSlice(r *bytes.Reader) error {
+	var header struct {
+		Keys, Flags uint32
+		_           uint32
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+
+	slice := Slice{Name: readAseString(r)}
+	for i := 0; i < int(header.Keys); i++ {
+		var key struct {
+			Frame uint32
+			X, Y  int32
+			W, H  uint32
+		}
+
+		if err := binary.Read(r, binary.LittleEndian, &key); err != nil {
+			return err
+		}
+
+		sliceKey := SliceKey{
+			Frame: int32(key.Frame),
+			X:     int(key.X),
+			Y:     int(key.Y),
+			W:     int(key.W),
+			H:     int(key.H),
+		}
+
+		if header.Flags&aseSliceNineSlice != 0 {
+			var center struct {
+				X, Y int32
+				W, H uint32
+			}
+
+			if err := binary.Read(r, binary.LittleEndian, &center); err != nil {
+				return err
+			}
+
+			sliceKey.HasCenter = true
+			sliceKey.CenterX, sliceKey.CenterY = int(center.X), int(center.Y)
+			sliceKey.CenterW, sliceKey.CenterH = int(center.W), int(center.H)
+		}
+
+		if header.Flags&aseSlicePivot != 0 {
+			var pivot [2]int32
+			if err := binary.Read(r, binary.LittleEndian, &pivot); err != nil {
+				return err
+			}
+
+			sliceKey.HasPivot = true
+			sliceKey.PivotX, sliceKey.PivotY = int(pivot[0]), int(pivot[1])
+		}
+
+		slice.Keys = append(slice.Keys, sliceKey)
+	}
+
+	d.file.Slices = append(d.file.Slices, slice)
+
+	index := len(d.file.Slices) - 1
+	d.userData = func(text string, color int64, hasColor bool) {
+		d.file.Slices[index].Data = UserData(text)
+		if hasColor {
+			d.file.Slices[index].Color = color
+		}
+	}
+
+	return nil
+}
+
+func (d *asepriteDecoder) readUserData(r *bytes.Reader) error {
+	var flags uint32
+	if err := binary.Read(r, binary.LittleEndian, &flags); err != nil {
+		return err
+	}
+
+	var text string
+	if flags&aseUserDataText != 0 {
+		text = readAseString(r)
+	}
+
+	var c int64
+	if flags&aseUserDataColor != 0 {
+		rgba := [4]uint8{}
+		if err := binary.Read(r, binary.LittleEndian, &rgba); err != nil {
+			return err
+		}
+
+		c = int64(rgba[0])<<24 | int64(rgba[1])<<16 | int64(rgba[2])<<8 | int64(rgba[3])
+	}
+
+	if d.userData != nil {
+		d.userData(text, c, flags&aseUserDataColor != 0)
+	}
+
+	return nil
+}
+
+// atlas composites every frame and lays them out in a grid on a single image, updating the File to match.
+func (d *asepriteDecoder) atlas() *image.RGBA {
+	f := d.file
+	w, h := int(d.header.Width), int(d.header.Height)
+	img, positions := newAtlas(len(f.Frames), w, h)
+
+	f.Width = int32(img.Bounds().Dx())
+	f.Height = int32(img.Bounds().Dy())
+
+	for i, pos := range positions {
+		f.Frames[i].X = pos.X
+		f.Frames[i].Y = pos.Y
+
+		cels := d.cels[i]
+		sort.SliceStable(cels, func(a, b int) bool {
+			oa, ob := cels[a].layer+cels[a].z, cels[b].layer+cels[b].z
+			if oa == ob {
+				return cels[a].z < cels[b].z
+			}
+
+			return oa < ob
+		})
+
+		frameRect := image.Rect(pos.X, pos.Y, pos.X+w, pos.Y+h)
+		for _, cel := range cels {
+			if cel.layer >= len(d.layers) {
+				continue
+			}
+
+			layer := d.layers[cel.layer]
+			if !layer.visible || layer.kind == aseLayerGroup {
+				continue
+			}
+
+			opacity := uint8(int(cel.opacity) * int(layer.opacity) / 255)
+			dst := image.Rect(cel.x, cel.y, cel.x+cel.img.Rect.Dx(), cel.y+cel.img.Rect.Dy()).Add(pos).Intersect(frameRect)
+			src := dst.Min.Sub(pos).Sub(image.Pt(cel.x, cel.y))
+
+			if layer.blendMode == BlendNormal {
+				draw.DrawMask(img, dst, cel.img, src, image.NewUniform(color.Alpha{opacity}), image.Point{}, draw.Over)
+				continue
+			}
+
+			for y := dst.Min.Y; y < dst.Max.Y; y++ {
+				for x := dst.Min.X; x < dst.Max.X; x++ {
+					backdrop := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+					c := cel.img.NRGBAAt(src.X+x-dst.Min.X, src.Y+y-dst.Min.Y)
+					img.Set(x, y, layer.blendMode.Apply(backdrop, c, opacity))
+				}
+			}
+		}
+	}
+
+	return img
+}
+
+// newAtlas returns an image big enough to hold n frames of w x h pixels laid out in a grid that is as square as possible,
+// and the position of every frame in it.
+func newAtlas(n, w, h int) (*image.RGBA, []image.Point) {
+	if n == 0 {
+		return image.NewRGBA(image.Rect(0, 0, 0, 0)), nil
+	}
+
+	cols := int(math.Ceil(math.Sqrt(float64(n))))
+	rows := (n + cols - 1) / cols
+
+	positions := make([]image.Point, n)
+	for i := range positions {
+		positions[i] = image.Pt((i%cols)*w, (i/cols)*h)
+	}
+
+	return image.NewRGBA(image.Rect(0, 0, cols*w, rows*h)), positions
+}
+
+func readAseString(r io.Reader) string {
+	var length uint16
+	if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+		return ""
+	}
+
+	b, _ := io.ReadAll(io.LimitReader(r, int64(length)))
+	return string(b)
+}
@@ -0,0 +1,79 @@
+package sprite
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAsepriteBinary(t *testing.T) {
+	f, img, err := OpenAsepriteBinary("example/16x16Deliveryman.aseprite")
+	require.NoError(t, err)
+
+	expected, err := OpenAseprite("example/16x16Deliveryman.json")
+	require.NoError(t, err)
+
+	assert.Equal(t, f.Path, "example/16x16Deliveryman.aseprite")
+	assert.Equal(t, f.FrameWidth, expected.FrameWidth)
+	assert.Equal(t, f.FrameHeight, expected.FrameHeight)
+	assert.Equal(t, f.Width, int32(img.Bounds().Dx()))
+	assert.Equal(t, f.Height, int32(img.Bounds().Dy()))
+	assert.Equal(t, f.Layers, expected.Layers)
+
+	require.Len(t, f.Frames, len(expected.Frames))
+	for i, frame := range f.Frames {
+		assert.Equal(t, frame.Duration, expected.Frames[i].Duration)
+	}
+
+	require.Len(t, f.Tags, len(expected.Tags))
+	for name, tag := range expected.Tags {
+		require.Contains(t, f.Tags, name)
+		assert.Equal(t, f.Tags[name].Start, tag.Start)
+		assert.Equal(t, f.Tags[name].End, tag.End)
+		assert.Equal(t, f.Tags[name].Direction, tag.Direction)
+	}
+
+	r, err := os.Open("example/16x16Deliveryman.png")
+	require.NoError(t, err)
+	defer r.Close()
+
+	sheet, err := png.Decode(r)
+	require.NoError(t, err)
+
+	for i, frame := range f.Frames {
+		want := expected.Frames[i]
+		for y := 0; y < int(f.FrameHeight); y++ {
+			for x := 0; x < int(f.FrameWidth); x++ {
+				assert.Equal(t,
+					color.NRGBAModel.Convert(sheet.At(want.X+x, want.Y+y)),
+					color.NRGBAModel.Convert(img.At(frame.X+x, frame.Y+y)),
+					"frame %d, pixel %d,%d", i, x, y,
+				)
+			}
+		}
+	}
+}
+
+func TestReadAsepriteBinaryInvalid(t *testing.T) {
+	_, _, err := ReadAsepriteBinary(bytes.NewReader(make([]byte, 128)))
+	assert.ErrorIs(t, err, ErrInvalidAseprite)
+}
+
+func TestNewAtlas(t *testing.T) {
+	img, positions := newAtlas(5, 16, 8)
+	assert.Equal(t, img.Bounds(), image.Rect(0, 0, 48, 16))
+	assert.Equal(t, positions, []image.Point{{0, 0}, {16, 0}, {32, 0}, {0, 8}, {16, 8}})
+}
+
+func TestReadOldPaletteTruncated(t *testing.T) {
+	d := &asepriteDecoder{palette: make([]color.NRGBA, 256)}
+	assert.Error(t, d.readOldPalette(bytes.NewReader([]byte{1, 0})))
+	assert.Error(t, d.readOldPalette(bytes.NewReader([]byte{1, 0, 0, 2, 255, 0, 0})))
+	assert.Equal(t, d.palette[0], color.NRGBA{255, 0, 0, 255})
+}
@@ -0,0 +1,203 @@
+package sprite
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// OpenAtlas will use os.Open() to read the libGDX / Spine .atlas file path specified. Files created with OpenAtlas()
+// will put the atlas filepath used in the Path field. duration is the duration in seconds of every frame, as the format
+// doesn't have timing.
+func OpenAtlas(atlasPath string, duration float32) (*File, error) {
+	r, err := os.Open(atlasPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+
+	f, err := ReadAtlas(r, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	f.Path = atlasPath
+	return f, nil
+}
+
+// ReadAtlas returns a *File for a given libGDX / Spine texture atlas, in both the legacy format (xy, size, orig, offset)
+// and the one used since libGDX 1.9.13 (bounds, offsets). Every page of the atlas becomes one of the File's Pages.
+// Regions sharing a name are played as a Tag named after them, ordered by their index, even across pages.
+func ReadAtlas(r io.Reader, duration float32) (*File, error) {
+	i := &atlasImporter{duration: duration, file: &File{}}
+	return i.loadFile(r)
+}
+
+type atlasImporter struct {
+	duration float32
+
+	file   *File
+	page   int
+	frames []namedFrame
+	region *atlasRegion
+
+	// sizeFromFrames is set while a page doesn't have its size, so it's the area covered by its frames.
+	sizeFromFrames bool
+}
+
+// atlasRegion holds the fields of a region until all of them are read, as they depend on each other.
+type atlasRegion struct {
+	name             string
+	x, y, w, h       int
+	origW, origH     int
+	offsetX, offsetY int
+	rotate           bool
+	index            int
+}
+
+func (i *atlasImporter) loadFile(r io.Reader) (*File, error) {
+	i.page = -1
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+
+		// Pages are separated by a blank line.
+		if line == "" {
+			i.endPage()
+			continue
+		}
+
+		key, value, isField := i.parseField(line)
+		switch {
+		case i.page < 0:
+			i.page = i.file.addPage(filepath.Clean(line), 0, 0)
+			i.sizeFromFrames = true
+		case isField && i.region == nil:
+			if key == "size" {
+				v := parseAtlasInts(value)
+				i.setPageSize(int32(v[0]), int32(v[1]))
+				i.sizeFromFrames = false
+			}
+		case isField:
+			i.parseRegionField(key, value)
+		default:
+			i.endRegion()
+			i.region = &atlasRegion{name: line, index: -1}
+		}
+	}
+
+	i.endPage()
+	i.file.setNamedFrames(i.frames)
+	return i.file, s.Err()
+}
+
+func (i *atlasImporter) setPageSize(w, h int32) {
+	i.file.Pages[i.page].Width, i.file.Pages[i.page].Height = w, h
+	if i.page == 0 {
+		i.file.Width, i.file.Height = w, h
+	}
+}
+
+func (i *atlasImporter) parseField(line string) (string, string, bool) {
+	c := strings.Index(line, ":")
+	if c < 0 {
+		return "", "", false
+	}
+
+	return strings.TrimSpace(line[:c]), strings.TrimSpace(line[c+1:]), true
+}
+
+func (i *atlasImporter) parseRegionField(key, value string) {
+	v := parseAtlasInts(value)
+	r := i.region
+
+	switch key {
+	case "xy":
+		r.x, r.y = v[0], v[1]
+	case "size":
+		r.w, r.h = v[0], v[1]
+	case "bounds":
+		r.x, r.y, r.w, r.h = v[0], v[1], v[2], v[3]
+	case "orig":
+		r.origW, r.origH = v[0], v[1]
+	case "offset":
+		r.offsetX, r.offsetY = v[0], v[1]
+	case "offsets":
+		r.offsetX, r.offsetY, r.origW, r.origH = v[0], v[1], v[2], v[3]
+	case "rotate":
+		r.rotate = value == "true" || value == "90"
+	case "index":
+		r.index = v[0]
+	}
+}
+
+func (i *atlasImporter) endRegion() {
+	r := i.region
+	if r == nil {
+		return
+	}
+
+	i.region = nil
+	if r.origW == 0 && r.origH == 0 {
+		r.origW, r.origH = r.w, r.h
+	}
+
+	// libGDX offsets are measured from the bottom-left corner of the original image, and regions are rotated
+	// counter-clockwise.
+	frame := Frame{
+		Filename:   r.name,
+		X:          r.x,
+		Y:          r.y,
+		W:          r.w,
+		H:          r.h,
+		Duration:   i.duration,
+		Rotated:    r.rotate,
+		RotatedCCW: r.rotate,
+		Trimmed:    r.w != r.origW || r.h != r.origH,
+		OffsetX:    r.offsetX,
+		OffsetY:    r.origH - r.h - r.offsetY,
+		SourceW:    r.origW,
+		SourceH:    r.origH,
+		Page:       i.page,
+	}
+
+	i.frames = append(i.frames, namedFrame{Frame: frame, tag: r.name, index: r.index})
+
+	if i.sizeFromFrames {
+		rect := i.file.frameRect(frame)
+		w, h := i.file.PageSize(i.page)
+		if int32(rect.Max.X) > w {
+			w = int32(rect.Max.X)
+		}
+
+		if int32(rect.Max.Y) > h {
+			h = int32(rect.Max.Y)
+		}
+
+		i.setPageSize(w, h)
+	}
+}
+
+func (i *atlasImporter) endPage() {
+	i.endRegion()
+	i.page = -1
+}
+
+// parseAtlasInts parses a list of comma separated numbers, always returning at least four values.
+func parseAtlasInts(value string) []int {
+	values := make([]int, 4)
+	for n, v := range strings.Split(value, ",") {
+		if n >= len(values) {
+			break
+		}
+
+		values[n], _ = strconv.Atoi(strings.TrimSpace(v))
+	}
+
+	return values
+}
@@ -0,0 +1,138 @@
+package sprite
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadAtlas(t *testing.T) {
+	f, err := ReadAtlas(strings.NewReader(atlasLegacy), 0.1)
+	require.NoError(t, err)
+
+	assert.Equal(t, f.ImagePath, "hero.png")
+	assert.Equal(t, f.Width, int32(256))
+	assert.Equal(t, f.Height, int32(128))
+	assert.Equal(t, f.Pages, []Page{{"hero.png", 256, 128}, {"hero2.png", 68, 34}})
+
+	require.Len(t, f.Frames, 5)
+	assert.Equal(t, f.Frames[0].X, 2)
+	assert.Equal(t, f.Frames[1].X, 36)
+	assert.Equal(t, f.Frames[2].X, 36)
+	assert.Equal(t, f.Frames[2].Page, 1)
+	assert.Equal(t, f.Frames[3], Frame{
+		Filename: "idle", X: 70, Y: 2, W: 20, H: 28, Duration: 0.1, Rotated: true, RotatedCCW: true,
+		Trimmed: true, OffsetX: 6, OffsetY: 1, SourceW: 32, SourceH: 32,
+	})
+	assert.Equal(t, f.Frames[4].Filename, "jump")
+	assert.Equal(t, f.Frames[4].Page, 1)
+
+	require.Len(t, f.Tags, 4)
+	assert.Equal(t, f.Tags["walk"].Start, 0)
+	assert.Equal(t, f.Tags["walk"].End, 2)
+	assert.Equal(t, f.Tags["idle"].Start, 3)
+	assert.Equal(t, f.Tags["jump"].Start, 4)
+
+	p := f.CreatePlayer()
+	require.NoError(t, p.Play("walk"))
+	p.SetFrameIndex(2)
+	u, v := p.CurrentUVCoords()
+	assert.Equal(t, []float64{36.0 / 68, 2.0 / 34}, []float64{u, v})
+}
+
+func TestReadAtlasNewFormat(t *testing.T) {
+	legacy, err := ReadAtlas(strings.NewReader(atlasLegacy), 0.1)
+	require.NoError(t, err)
+
+	f, err := ReadAtlas(strings.NewReader(atlasNew), 0.1)
+	require.NoError(t, err)
+
+	assert.Equal(t, legacy.Pages, f.Pages)
+	assert.Equal(t, legacy.Frames, f.Frames)
+}
+
+func TestFrameGeoMCounterClockwise(t *testing.T) {
+	g := frameGeoM(Frame{W: 20, H: 28, Rotated: true, RotatedCCW: true})
+
+	// The top-left corner of a frame stored rotated counter-clockwise is its top-right corner.
+	x, y := g.Apply(0, 0)
+	assert.InDelta(t, 20, x, 1e-9)
+	assert.InDelta(t, 0, y, 1e-9)
+
+	x, y = g.Apply(28, 20)
+	assert.InDelta(t, 0, x, 1e-9)
+	assert.InDelta(t, 28, y, 1e-9)
+}
+
+var atlasLegacy = `
+hero.png
+size: 256, 128
+format: RGBA8888
+filter: Nearest, Nearest
+repeat: none
+walk
+  rotate: false
+  xy: 36, 2
+  size: 32, 32
+  orig: 32, 32
+  offset: 0, 0
+  index: 1
+walk
+  rotate: false
+  xy: 2, 2
+  size: 32, 32
+  orig: 32, 32
+  offset: 0, 0
+  index: 0
+idle
+  rotate: true
+  xy: 70, 2
+  size: 20, 28
+  orig: 32, 32
+  offset: 6, 3
+  index: -1
+
+hero2.png
+format: RGBA8888
+filter: Nearest, Nearest
+repeat: none
+jump
+  rotate: false
+  xy: 2, 2
+  size: 32, 32
+  orig: 32, 32
+  offset: 0, 0
+  index: -1
+walk
+  rotate: false
+  xy: 36, 2
+  size: 32, 32
+  orig: 32, 32
+  offset: 0, 0
+  index: 2
+`
+
+var atlasNew = `hero.png
+size:256,128
+filter:Nearest,Nearest
+walk
+bounds:36,2,32,32
+index:1
+walk
+bounds:2,2,32,32
+index:0
+idle
+bounds:70,2,20,28
+offsets:6,3,32,32
+rotate:90
+
+hero2.png
+filter:Nearest,Nearest
+jump
+bounds:2,2,32,32
+walk
+bounds:36,2,32,32
+index:2
+`
@@ -0,0 +1,398 @@
+package sprite
+
+import (
+	"image/color"
+	"math"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// BlendMode is the blend mode of a Layer, as defined in Aseprite. The values match the ones stored in .aseprite files.
+type BlendMode int
+
+const (
+	BlendNormal BlendMode = iota
+	BlendMultiply
+	BlendScreen
+	BlendOverlay
+	BlendDarken
+	BlendLighten
+	BlendColorDodge
+	BlendColorBurn
+	BlendHardLight
+	BlendSoftLight
+	BlendDifference
+	BlendExclusion
+	BlendHue
+	BlendSaturation
+	BlendColor
+	BlendLuminosity
+	BlendAddition
+	BlendSubtract
+	BlendDivide
+)
+
+// blendModeNames are the names of the BlendModes used by the Aseprite JSON export, in order.
+var blendModeNames = []string{
+	"normal", "multiply", "screen", "overlay", "darken", "lighten", "color_dodge", "color_burn", "hard_light",
+	"soft_light", "difference", "exclusion", "hue", "saturation", "color", "luminosity", "addition", "subtract", "divide",
+}
+
+// ParseBlendMode returns the BlendMode for a name used by the Aseprite JSON export ("multiply", "color_dodge"...).
+// Unknown names are BlendNormal.
+func ParseBlendMode(name string) BlendMode {
+	for i, n := range blendModeNames {
+		if n == name {
+			return BlendMode(i)
+		}
+	}
+
+	return BlendNormal
+}
+
+// String returns the name of the BlendMode as used by the Aseprite JSON export.
+func (m BlendMode) String() string {
+	if m < 0 || int(m) >= len(blendModeNames) {
+		return blendModeNames[BlendNormal]
+	}
+
+	return blendModeNames[m]
+}
+
+// Apply blends src over backdrop with the given opacity, following the W3C compositing and blending formulas that
+// Aseprite implements. It's the reference for the shader used when drawing layered sprites.
+func (m BlendMode) Apply(backdrop, src color.NRGBA, opacity uint8) color.NRGBA {
+	ab := float64(backdrop.A) / 255
+	as := float64(src.A) / 255 * float64(opacity) / 255
+	cb := [3]float64{float64(backdrop.R) / 255, float64(backdrop.G) / 255, float64(backdrop.B) / 255}
+	cs := [3]float64{float64(src.R) / 255, float64(src.G) / 255, float64(src.B) / 255}
+
+	blended := m.blend(cb, cs)
+
+	ao := as + ab*(1-as)
+	if ao == 0 {
+		return color.NRGBA{}
+	}
+
+	out := color.NRGBA{A: uint8(math.Round(ao * 255))}
+	for i, c := range []*uint8{&out.R, &out.G, &out.B} {
+		co := cs[i]*as*(1-ab) + cb[i]*ab*(1-as) + as*ab*blended[i]
+		*c = uint8(math.Round(clamp01(co/ao) * 255))
+	}
+
+	return out
+}
+
+// blend returns the result of blending the (non-premultiplied) colors of the backdrop and the source.
+func (m BlendMode) blend(cb, cs [3]float64) [3]float64 {
+	switch m {
+	case BlendHue:
+		return setLum(setSat(cs, sat(cb)), lum(cb))
+	case BlendSaturation:
+		return setLum(setSat(cb, sat(cs)), lum(cb))
+	case BlendColor:
+		return setLum(cs, lum(cb))
+	case BlendLuminosity:
+		return setLum(cb, lum(cs))
+	}
+
+	var out [3]float64
+	for i := range out {
+		out[i] = m.blendChannel(cb[i], cs[i])
+	}
+
+	return out
+}
+
+// blendChannel returns the result of blending a channel of the backdrop and the source for separable blend modes.
+func (m BlendMode) blendChannel(b, s float64) float64 {
+	switch m {
+	case BlendMultiply:
+		return b * s
+	case BlendScreen:
+		return b + s - b*s
+	case BlendOverlay:
+		return hardLight(s, b)
+	case BlendDarken:
+		return math.Min(b, s)
+	case BlendLighten:
+		return math.Max(b, s)
+	case BlendColorDodge:
+		if b == 0 {
+			return 0
+		} else if s >= 1 {
+			return 1
+		}
+
+		return math.Min(1, b/(1-s))
+	case BlendColorBurn:
+		if b >= 1 {
+			return 1
+		} else if s <= 0 {
+			return 0
+		}
+
+		return 1 - math.Min(1, (1-b)/s)
+	case BlendHardLight:
+		return hardLight(b, s)
+	case BlendSoftLight:
+		if s <= 0.5 {
+			return b - (1-2*s)*b*(1-b)
+		}
+
+		d := math.Sqrt(b)
+		if b <= 0.25 {
+			d = ((16*b-12)*b + 4) * b
+		}
+
+		return b + (2*s-1)*(d-b)
+	case BlendDifference:
+		return math.Abs(b - s)
+	case BlendExclusion:
+		return b + s - 2*b*s
+	case BlendAddition:
+		return math.Min(1, b+s)
+	case BlendSubtract:
+		return math.Max(0, b-s)
+	case BlendDivide:
+		if b == 0 {
+			return 0
+		} else if b >= s {
+			return 1
+		}
+
+		return b / s
+	}
+
+	return s
+}
+
+func hardLight(b, s float64) float64 {
+	if s <= 0.5 {
+		return b * 2 * s
+	}
+
+	s = 2*s - 1
+	return b + s - b*s
+}
+
+func lum(c [3]float64) float64 {
+	return 0.3*c[0] + 0.59*c[1] + 0.11*c[2]
+}
+
+func sat(c [3]float64) float64 {
+	return math.Max(c[0], math.Max(c[1], c[2])) - math.Min(c[0], math.Min(c[1], c[2]))
+}
+
+func setLum(c [3]float64, l float64) [3]float64 {
+	d := l - lum(c)
+	c = [3]float64{c[0] + d, c[1] + d, c[2] + d}
+
+	l = lum(c)
+	n := math.Min(c[0], math.Min(c[1], c[2]))
+	x := math.Max(c[0], math.Max(c[1], c[2]))
+	for i := range c {
+		if n < 0 {
+			c[i] = l + (c[i]-l)*l/(l-n)
+		}
+
+		if x > 1 {
+			c[i] = l + (c[i]-l)*(1-l)/(x-l)
+		}
+	}
+
+	return c
+}
+
+func setSat(c [3]float64, s float64) [3]float64 {
+	n := math.Min(c[0], math.Min(c[1], c[2]))
+	x := math.Max(c[0], math.Max(c[1], c[2]))
+	if x <= n {
+		return [3]float64{}
+	}
+
+	return [3]float64{(c[0] - n) * s / (x - n), (c[1] - n) * s / (x - n), (c[2] - n) * s / (x - n)}
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// ebitenBlend returns the ebiten blending that is exactly the BlendMode, if there's one.
+func (m BlendMode) ebitenBlend() (ebiten.Blend, bool) {
+	switch m {
+	case BlendNormal:
+		return ebiten.BlendSourceOver, true
+	case BlendScreen:
+		// With premultiplied colors, screen is cs + cb - cs*cb.
+		return ebiten.Blend{
+			BlendFactorSourceRGB:        ebiten.BlendFactorOne,
+			BlendFactorSourceAlpha:      ebiten.BlendFactorOne,
+			BlendFactorDestinationRGB:   ebiten.BlendFactorOneMinusSourceColor,
+			BlendFactorDestinationAlpha: ebiten.BlendFactorOneMinusSourceAlpha,
+			BlendOperationRGB:           ebiten.BlendOperationAdd,
+			BlendOperationAlpha:         ebiten.BlendOperationAdd,
+		}, true
+	}
+
+	return ebiten.Blend{}, false
+}
+
+var (
+	blendShader     *ebiten.Shader
+	blendShaderErr  error
+	blendShaderOnce sync.Once
+)
+
+// getBlendShader returns the shader used to draw the BlendModes without an ebiten blending, compiling it the first
+// time.
+func getBlendShader() (*ebiten.Shader, error) {
+	blendShaderOnce.Do(func() {
+		blendShader, blendShaderErr = ebiten.NewShader([]byte(blendShaderSource))
+	})
+
+	return blendShader, blendShaderErr
+}
+
+// blendShaderSource blends the layer in the first image over the backdrop in the second one, replacing the
+// destination. It mirrors BlendMode.Apply.
+const blendShaderSource = `//kage:unit pixels
+
+package main
+
+var Mode int
+var Opacity float
+
+func lum(c vec3) float {
+	return dot(c, vec3(0.3, 0.59, 0.11))
+}
+
+func sat(c vec3) float {
+	return max(max(c.r, c.g), c.b) - min(min(c.r, c.g), c.b)
+}
+
+func setLum(c vec3, l float) vec3 {
+	r := c + vec3(l-lum(c))
+	ll := lum(r)
+	n := min(min(r.r, r.g), r.b)
+	x := max(max(r.r, r.g), r.b)
+	if n < 0 {
+		r = ll + (r-ll)*ll/(ll-n)
+	}
+	if x > 1 {
+		r = ll + (r-ll)*(1-ll)/(x-ll)
+	}
+	return r
+}
+
+func setSat(c vec3, s float) vec3 {
+	n := min(min(c.r, c.g), c.b)
+	x := max(max(c.r, c.g), c.b)
+	if x <= n {
+		return vec3(0)
+	}
+	return (c - n) * s / (x - n)
+}
+
+func hardLight(b, s float) float {
+	if s <= 0.5 {
+		return b * 2 * s
+	}
+	t := 2*s - 1
+	return b + t - b*t
+}
+
+func blendChannel(b, s float) float {
+	if Mode == 1 {
+		return b * s
+	} else if Mode == 2 {
+		return b + s - b*s
+	} else if Mode == 3 {
+		return hardLight(s, b)
+	} else if Mode == 4 {
+		return min(b, s)
+	} else if Mode == 5 {
+		return max(b, s)
+	} else if Mode == 6 {
+		if b == 0 {
+			return 0
+		} else if s >= 1 {
+			return 1
+		}
+		return min(1, b/(1-s))
+	} else if Mode == 7 {
+		if b >= 1 {
+			return 1
+		} else if s <= 0 {
+			return 0
+		}
+		return 1 - min(1, (1-b)/s)
+	} else if Mode == 8 {
+		return hardLight(b, s)
+	} else if Mode == 9 {
+		if s <= 0.5 {
+			return b - (1-2*s)*b*(1-b)
+		}
+		d := sqrt(b)
+		if b <= 0.25 {
+			d = ((16*b-12)*b + 4) * b
+		}
+		return b + (2*s-1)*(d-b)
+	} else if Mode == 10 {
+		return abs(b - s)
+	} else if Mode == 11 {
+		return b + s - 2*b*s
+	} else if Mode == 16 {
+		return min(1, b+s)
+	} else if Mode == 17 {
+		return max(0, b-s)
+	} else if Mode == 18 {
+		if b == 0 {
+			return 0
+		} else if b >= s {
+			return 1
+		}
+		return b / s
+	}
+	return s
+}
+
+func blend(cb, cs vec3) vec3 {
+	if Mode == 12 {
+		return setLum(setSat(cs, sat(cb)), lum(cb))
+	} else if Mode == 13 {
+		return setLum(setSat(cb, sat(cs)), lum(cb))
+	} else if Mode == 14 {
+		return setLum(cs, lum(cb))
+	} else if Mode == 15 {
+		return setLum(cb, lum(cs))
+	}
+	return vec3(blendChannel(cb.r, cs.r), blendChannel(cb.g, cs.g), blendChannel(cb.b, cs.b))
+}
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	s := imageSrc0At(srcPos)
+	b := imageSrc1At(srcPos - imageSrc0Origin() + imageSrc1Origin())
+
+	as := s.a * Opacity
+	ab := b.a
+	ao := as + ab*(1-as)
+	if ao == 0 {
+		return vec4(0)
+	}
+
+	// Colors are premultiplied.
+	cs := vec3(0)
+	if s.a > 0 {
+		cs = s.rgb / s.a
+	}
+	cb := vec3(0)
+	if b.a > 0 {
+		cb = b.rgb / b.a
+	}
+
+	co := cs*as*(1-ab) + cb*ab*(1-as) + as*ab*blend(cb, cs)
+	return vec4(clamp(co, vec3(0), vec3(ao)), ao)
+}
+`
@@ -0,0 +1,78 @@
+package sprite
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBlendMode(t *testing.T) {
+	assert.Equal(t, ParseBlendMode("multiply"), BlendMultiply)
+	assert.Equal(t, ParseBlendMode("color_dodge"), BlendColorDodge)
+	assert.Equal(t, ParseBlendMode("divide"), BlendDivide)
+	assert.Equal(t, ParseBlendMode("unknown"), BlendNormal)
+
+	for m := BlendNormal; m <= BlendDivide; m++ {
+		assert.Equal(t, ParseBlendMode(m.String()), m)
+	}
+
+	assert.Equal(t, BlendMode(42).String(), "normal")
+}
+
+func TestBlendModeApply(t *testing.T) {
+	backdrop := color.NRGBA{R: 200, G: 100, B: 0, A: 255}
+	src := color.NRGBA{R: 100, G: 255, B: 50, A: 255}
+
+	testCases := []struct {
+		mode     BlendMode
+		expected color.NRGBA
+	}{
+		{BlendNormal, color.NRGBA{R: 100, G: 255, B: 50, A: 255}},
+		{BlendMultiply, color.NRGBA{R: 78, G: 100, B: 0, A: 255}},
+		{BlendScreen, color.NRGBA{R: 222, G: 255, B: 50, A: 255}},
+		{BlendDarken, color.NRGBA{R: 100, G: 100, B: 0, A: 255}},
+		{BlendLighten, color.NRGBA{R: 200, G: 255, B: 50, A: 255}},
+		{BlendDifference, color.NRGBA{R: 100, G: 155, B: 50, A: 255}},
+		{BlendAddition, color.NRGBA{R: 255, G: 255, B: 50, A: 255}},
+		{BlendSubtract, color.NRGBA{R: 100, G: 0, B: 0, A: 255}},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, tc.mode.Apply(backdrop, src, 255), tc.mode.String())
+	}
+}
+
+func TestBlendModeApplyOpacity(t *testing.T) {
+	backdrop := color.NRGBA{R: 200, G: 200, B: 200, A: 255}
+	src := color.NRGBA{R: 0, G: 0, B: 0, A: 255}
+
+	assert.Equal(t, BlendMultiply.Apply(backdrop, src, 0), backdrop)
+	assert.Equal(t, BlendMultiply.Apply(backdrop, src, 128), color.NRGBA{R: 100, G: 100, B: 100, A: 255})
+}
+
+func TestBlendModeApplyTransparentBackdrop(t *testing.T) {
+	src := color.NRGBA{R: 10, G: 20, B: 30, A: 128}
+
+	// Without a backdrop every mode is a plain copy of the source.
+	for m := BlendNormal; m <= BlendDivide; m++ {
+		assert.Equal(t, m.Apply(color.NRGBA{}, src, 255), src, m.String())
+	}
+
+	assert.Equal(t, BlendNormal.Apply(color.NRGBA{}, color.NRGBA{}, 255), color.NRGBA{})
+}
+
+func TestBlendModeApplyNonSeparable(t *testing.T) {
+	gray := color.NRGBA{R: 128, G: 128, B: 128, A: 255}
+	red := color.NRGBA{R: 255, A: 255}
+
+	// A gray source has no hue nor saturation, so the backdrop becomes gray keeping its luminosity.
+	got := BlendSaturation.Apply(red, gray, 255)
+	assert.Equal(t, got.R, got.G)
+	assert.Equal(t, got.G, got.B)
+
+	// Luminosity keeps the hue of the backdrop.
+	got = BlendLuminosity.Apply(red, gray, 255)
+	assert.True(t, got.R > got.G)
+	assert.Equal(t, got.G, got.B)
+}
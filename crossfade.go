@@ -0,0 +1,76 @@
+package sprite
+
+// Crossfade is the state of a crossfade between the animation that was playing and the current one.
+type Crossfade struct {
+	From     *Player // The outgoing animation, which keeps playing until the crossfade ends.
+	Elapsed  float32 // The time in seconds since the crossfade started.
+	Duration float32 // The duration of the crossfade, in seconds.
+}
+
+// Weight returns the weight of the incoming animation, from 0 when the crossfade starts to 1 when it ends. The
+// outgoing animation has the rest.
+func (c Crossfade) Weight() float32 {
+	if c.Elapsed >= c.Duration {
+		return 1
+	}
+
+	return c.Elapsed / c.Duration
+}
+
+// CrossfadeTo plays the specified tag name like Play, but fading it in over duration seconds while the animation that
+// was playing keeps advancing and fades out. Without an animation playing, or with a duration of 0, it's the same as
+// Play.
+func (p *Player) CrossfadeTo(tagName string, duration float32, opts ...PlayOptions) error {
+	t, ok := p.File.Tags[tagName]
+	if !ok {
+		return ErrNoTagByName
+	}
+
+	if p.CurrentTag == nil || duration <= 0 || p.CurrentTag == t && !p.finished {
+		return p.Play(tagName, opts...)
+	}
+
+	// The outgoing animation only needs to advance and to be drawn like the Player.
+	from := p.Clone()
+	from.OnLoop = nil
+	from.OnFrameChange = nil
+	from.OnTagEnter = nil
+	from.OnTagExit = nil
+	from.OnFinish = nil
+	from.OnQueueNext = nil
+	from.OnEvent = nil
+	from.queue = nil
+
+	if err := p.Play(tagName, opts...); err != nil {
+		return err
+	}
+
+	p.fade = &Crossfade{From: from, Duration: duration}
+	return nil
+}
+
+// Crossfade returns the crossfade in progress, and false if there's none. It can be used by OnDraw to draw both
+// animations in its own way: Crossfade().From is the outgoing one.
+func (p *Player) Crossfade() (Crossfade, bool) {
+	if p.fade == nil {
+		return Crossfade{}, false
+	}
+
+	return *p.fade, true
+}
+
+// updateCrossfade advances the outgoing animation of the crossfade in progress, ending the crossfade when its time is
+// up.
+func (p *Player) updateCrossfade(dt float32) {
+	if p.fade == nil {
+		return
+	}
+
+	p.fade.Elapsed += dt
+	if p.fade.Elapsed >= p.fade.Duration {
+		p.fade = nil
+		return
+	}
+
+	p.fade.From.Update(dt)
+}
@@ -0,0 +1,60 @@
+package sprite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrossfadeWeight(t *testing.T) {
+	assert.Equal(t, Crossfade{Elapsed: 0, Duration: 0.4}.Weight(), float32(0))
+	assert.Equal(t, Crossfade{Elapsed: 0.1, Duration: 0.4}.Weight(), float32(0.25))
+	assert.Equal(t, Crossfade{Elapsed: 0.5, Duration: 0.4}.Weight(), float32(1))
+}
+
+func TestPlayerCrossfadeTo(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteDirections))
+	require.NoError(t, err)
+
+	p := f.CreatePlayer()
+	loops := 0
+	p.OnLoop = func(p *Player) { loops++ }
+
+	// Without an animation playing there's nothing to fade from.
+	require.NoError(t, p.CrossfadeTo("reverse", 0.3))
+	_, ok := p.Crossfade()
+	assert.False(t, ok)
+
+	p.Update(0.1)
+	require.NoError(t, p.CrossfadeTo("forward", 0.3))
+	assert.ErrorIs(t, p.CrossfadeTo("missing", 0.3), ErrNoTagByName)
+
+	fade, ok := p.Crossfade()
+	require.True(t, ok)
+	assert.Equal(t, fade.From.CurrentTag.Name, "reverse")
+	assert.Equal(t, fade.From.FrameIndex, 2)
+	assert.Equal(t, p.CurrentTag.Name, "forward")
+	assert.Equal(t, p.FrameIndex, 0)
+
+	// Both animations advance, and only the current one calls back.
+	p.Update(0.1)
+	p.Update(0.1)
+	fade, ok = p.Crossfade()
+	require.True(t, ok)
+	assert.Equal(t, fade.From.FrameIndex, 3)
+	assert.Equal(t, p.FrameIndex, 2)
+	assert.InDelta(t, 2.0/3, fade.Weight(), 1e-6)
+	assert.Equal(t, loops, 0)
+
+	p.Update(0.1)
+	_, ok = p.Crossfade()
+	assert.False(t, ok)
+	assert.Equal(t, loops, 1)
+
+	// Playing a tag cuts the crossfade short.
+	require.NoError(t, p.CrossfadeTo("pingpong", 1))
+	require.NoError(t, p.Play("reverse"))
+	_, ok = p.Crossfade()
+	assert.False(t, ok)
+}
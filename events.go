@@ -0,0 +1,63 @@
+package sprite
+
+import (
+	"sort"
+	"strings"
+)
+
+// eventTagPrefix is the prefix of the names of the Tags that mark an event on their first frame ("event:footstep").
+const eventTagPrefix = "event:"
+
+// Event is a gameplay event authored in Aseprite, which fires when the frame it's on is shown.
+type Event struct {
+	Name    string
+	Payload UserData // The user data the event was defined with, for extra parameters.
+}
+
+// collectEvents adds the Events authored in the File to its Frames. Events come from:
+//   - cels and tags with an "event" value in their user data (as "event=footstep" or {"event": "footstep"}), on the
+//     frame of the cel or on the first frame of the tag;
+//   - tags named "event:name", usually a single frame long, on their first frame, with the tag's user data as
+//     payload.
+func (f *File) collectEvents() {
+	add := func(frame int, name string, payload UserData) {
+		if frame >= 0 && frame < len(f.Frames) && name != "" {
+			f.Frames[frame].Events = append(f.Frames[frame].Events, Event{Name: name, Payload: payload})
+		}
+	}
+
+	for _, layer := range f.Layers {
+		for _, cel := range layer.Cels {
+			if name, ok := cel.Data.Get("event"); ok {
+				add(cel.Frame, name, cel.Data)
+			}
+		}
+	}
+
+	// Tags are in a map, so they're sorted for events on the same frame to keep their order.
+	tags := make([]*Tag, 0, len(f.Tags))
+	for _, tag := range f.Tags {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Start != tags[j].Start {
+			return tags[i].Start < tags[j].Start
+		}
+		return tags[i].Name < tags[j].Name
+	})
+
+	for _, tag := range tags {
+		if strings.HasPrefix(tag.Name, eventTagPrefix) {
+			add(tag.Start, strings.TrimPrefix(tag.Name, eventTagPrefix), tag.Data)
+		} else if name, ok := tag.Data.Get("event"); ok {
+			add(tag.Start, name, tag.Data)
+		}
+	}
+}
+
+// fireEvents emits a MarkerEvent for each of the Events of the current frame.
+func (p *Player) fireEvents() {
+	for _, event := range p.File.Frames[p.FrameIndex].Events {
+		p.emit(&MarkerEvent{Player: p, Event: event})
+	}
+}
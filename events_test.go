@@ -0,0 +1,97 @@
+package sprite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var asepriteEvents = `{ "frames": [
+	{ "filename": "hero 0.aseprite", "frame": { "x": 0, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 100 },
+	{ "filename": "hero 1.aseprite", "frame": { "x": 16, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 100 },
+	{ "filename": "hero 2.aseprite", "frame": { "x": 32, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 100 },
+	{ "filename": "hero 3.aseprite", "frame": { "x": 48, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 100 }
+ ],
+ "meta": {
+	"image": "hero.png", "size": { "w": 64, "h": 16 },
+	"frameTags": [
+		{ "name": "walk", "from": 0, "to": 3, "direction": "forward" },
+		{ "name": "swing", "from": 1, "to": 1, "direction": "forward", "data": "event=swing" },
+		{ "name": "event:spawn", "from": 2, "to": 2, "direction": "forward", "data": "x=4" }
+	],
+	"layers": [
+		{ "name": "body", "opacity": 255, "blendMode": "normal", "cels": [
+			{ "frame": 0, "data": "event=footstep" },
+			{ "frame": 1, "data": "dust" },
+			{ "frame": 3, "data": "event=footstep, foot=right" }
+		] }
+	]
+ }
+}`
+
+func TestFrameEvents(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteEvents))
+	require.NoError(t, err)
+
+	assert.Equal(t, f.Frames[0].Events, []Event{{Name: "footstep", Payload: "event=footstep"}})
+	assert.Equal(t, f.Frames[1].Events, []Event{{Name: "swing", Payload: "event=swing"}})
+	assert.Equal(t, f.Frames[2].Events, []Event{{Name: "spawn", Payload: "x=4"}})
+	assert.Equal(t, f.Frames[3].Events, []Event{{Name: "footstep", Payload: "event=footstep, foot=right"}})
+}
+
+func TestPlayerOnEvent(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteEvents))
+	require.NoError(t, err)
+
+	var events []string
+	p := f.CreatePlayer()
+	p.OnEvent = func(p *Player, name string, payload UserData) {
+		events = append(events, name+"@"+string(rune('0'+p.FrameIndex)))
+	}
+
+	// The first frame fires its events when the tag starts.
+	require.NoError(t, p.Play("walk"))
+	assert.Equal(t, []string{"footstep@0"}, events)
+
+	// Frames skipped by a large dt fire theirs too.
+	events = nil
+	p.Update(0.35)
+	assert.Equal(t, p.FrameIndex, 3)
+	assert.Equal(t, []string{"swing@1", "spawn@2", "footstep@3"}, events)
+
+	events = nil
+	p.Update(0.05)
+	assert.Equal(t, []string{"footstep@0"}, events)
+
+	// As do the frames played backwards.
+	events = nil
+	p.PlaySpeed = -1
+	p.Update(0.1)
+	assert.Equal(t, []string{"footstep@3"}, events)
+}
+
+func TestPlayerOnEventCrossfade(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteEvents))
+	require.NoError(t, err)
+
+	var events []string
+	p := f.CreatePlayer()
+	p.OnEvent = func(from *Player, name string, payload UserData) {
+		assert.Same(t, p, from)
+		events = append(events, name)
+	}
+
+	require.NoError(t, p.Play("walk"))
+	p.Update(0.05)
+
+	// The outgoing animation moves on to the "swing" frame without firing it.
+	events = nil
+	require.NoError(t, p.CrossfadeTo("event:spawn", 0.3))
+	p.Update(0.1)
+	assert.Equal(t, []string{"spawn", "spawn"}, events)
+
+	fade, ok := p.Crossfade()
+	require.True(t, ok)
+	assert.Equal(t, fade.From.FrameIndex, 1)
+}
@@ -1,6 +1,7 @@
 package sprite
 
 import (
+	"image"
 	"os"
 	"path/filepath"
 	"sort"
@@ -19,19 +20,39 @@ const (
 	PlayBackward Direction = "reverse"
 	// PlayPingPong plays animation forward then backward
 	PlayPingPong Direction = "pingpong"
+	// PlayPingPongReverse plays animation backward then forward
+	PlayPingPongReverse Direction = "pingpong_reverse"
 )
 
+// pingPong returns true if the Direction bounces at the ends of the animation instead of wrapping around.
+func (d Direction) pingPong() bool {
+	return d == PlayPingPong || d == PlayPingPongReverse
+}
+
+// reversed returns true if the Direction starts playing from the end of the animation.
+func (d Direction) reversed() bool {
+	return d == PlayBackward || d == PlayPingPongReverse
+}
+
 // File contains all properties of an exported aseprite file. ImagePath is the absolute path to the image as reported by the exported
 // Aseprite JSON data. Path is the string used to open the File if it was opened with the Open() function; otherwise, it's blank.
 type File struct {
 	Path                    string          // Path to the file (exampleSprite.json); blank if the *File was loaded using Read().
 	ImagePath               string          // Path to the image associated with the Aseprite file (exampleSprite.png).
 	Width, Height           int32           // Overall width and height of the File.
-	FrameWidth, FrameHeight int32           // Width and height of the frames in the File.
+	FrameWidth, FrameHeight int32           // Width and height of the first frame; only meaningful if every frame has the same size.
 	Frames                  []Frame         // The animation Frames present in the File.
 	Tags                    map[string]*Tag // A map of Tags, with their names being the keys.
 	Layers                  []Layer         // A slice of Layers.
 	Slices                  []Slice         // A slice of the Slices present in the file.
+	Pages                   []Page          // The images the Frames are spread across; blank if there's only the one in ImagePath.
+}
+
+// Page is one of the images (textures) of a File that is split across several of them. Frames reference their page
+// by its index in File.Pages.
+type Page struct {
+	ImagePath     string // Path to the image of the page.
+	Width, Height int32  // Width and height of the image of the page.
 }
 
 // OpenAseprite will use os.ReadFile() to open the Aseprite JSON file path specified to parse the data. Returns a *goaseprite.File.
@@ -64,41 +85,57 @@ func (f *File) decode(data []byte) error {
 	f.ImagePath = filepath.Clean(gjson.Get(json, "meta.image").String())
 
 	frameNames := []string{}
+	frameDatas := []gjson.Result{}
 
 	f.Width = int32(gjson.Get(json, "meta.size.w").Num)
 	f.Height = int32(gjson.Get(json, "meta.size.h").Num)
 
 	for _, key := range gjson.Get(json, "meta.layers").Array() {
-		f.Layers = append(f.Layers, Layer{Name: key.Get("name").String(), Opacity: uint8(key.Get("opacity").Int()), BlendMode: key.Get("blendMode").String()})
-	}
-
-	for key := range gjson.Get(json, "frames").Map() {
-		frameNames = append(frameNames, key)
-	}
+		layer := Layer{
+			Name:      key.Get("name").String(),
+			Opacity:   uint8(key.Get("opacity").Int()),
+			BlendMode: ParseBlendMode(key.Get("blendMode").String()),
+			Data:      UserData(key.Get("data").String()),
+			Color:     parseColor(key.Get("color").String()),
+		}
 
-	sort.Slice(frameNames, func(i, j int) bool {
-		x := frameNames[i]
-		y := frameNames[j]
-		xfi := strings.LastIndex(x, " ") + 1
-		xli := strings.LastIndex(x, ".")
-		xv, _ := strconv.ParseInt(x[xfi:xli], 10, 32)
-		yfi := strings.LastIndex(y, " ") + 1
-		yli := strings.LastIndex(y, ".")
-		yv, _ := strconv.ParseInt(y[yfi:yli], 10, 32)
-		return xv < yv
-	})
+		for _, cel := range key.Get("cels").Array() {
+			layer.Cels = append(layer.Cels, Cel{
+				Frame: int(cel.Get("frame").Int()),
+				Data:  UserData(cel.Get("data").String()),
+				Color: parseColor(cel.Get("color").String()),
+			})
+		}
 
-	for _, key := range frameNames {
-		frameName := key
-		frameName = strings.Replace(frameName, ".", `\.`, -1)
-		frameData := gjson.Get(json, "frames."+frameName)
+		f.Layers = append(f.Layers, layer)
+	}
 
-		frame := Frame{}
-		frame.X = int(frameData.Get("frame.x").Num)
-		frame.Y = int(frameData.Get("frame.y").Num)
-		frame.Duration = float32(frameData.Get("duration").Num) / 1000
+	// The "Array" layout already lists the frames in order, while the "Hash" one is keyed by filename and has to be sorted.
+	frames := gjson.Get(json, "frames")
+	if frames.IsArray() {
+		for _, frameData := range frames.Array() {
+			frameNames = append(frameNames, frameData.Get("filename").String())
+			frameDatas = append(frameDatas, frameData)
+		}
+	} else {
+		frameByName := map[string]gjson.Result{}
+		frames.ForEach(func(key, value gjson.Result) bool {
+			frameNames = append(frameNames, key.String())
+			frameByName[key.String()] = value
+			return true
+		})
+
+		sort.SliceStable(frameNames, func(i, j int) bool {
+			return frameNumber(frameNames[i]) < frameNumber(frameNames[j])
+		})
+
+		for _, name := range frameNames {
+			frameDatas = append(frameDatas, frameByName[name])
+		}
+	}
 
-		f.Frames = append(f.Frames, frame)
+	for i, frameData := range frameDatas {
+		f.Frames = append(f.Frames, decodeFrame(frameNames[i], frameData))
 
 		// We want to set it only on the first frame loaded
 		if f.FrameWidth == 0 {
@@ -107,6 +144,8 @@ func (f *File) decode(data []byte) error {
 		}
 	}
 
+	f.splitLayers()
+
 	f.Tags = make(map[string]*Tag, 0)
 
 	// Default ("") animation
@@ -126,35 +165,296 @@ func (f *File) decode(data []byte) error {
 			Start:     int(anim.Get("from").Num),
 			End:       int(anim.Get("to").Num),
 			Direction: Direction(anim.Get("direction").Str),
+			Repeat:    int(anim.Get("repeat").Int()),
+			Data:      UserData(anim.Get("data").Str),
+			Color:     parseColor(anim.Get("color").Str),
 			File:      f,
 		}
 	}
 
 	for _, sliceData := range gjson.Get(json, "meta.slices").Array() {
-		color, _ := strconv.ParseInt("0x"+sliceData.Get("color").Str[1:], 0, 64)
-
 		newSlice := Slice{
 			Name:  sliceData.Get("name").Str,
-			Data:  sliceData.Get("data").Str,
-			Color: color,
+			Data:  UserData(sliceData.Get("data").Str),
+			Color: parseColor(sliceData.Get("color").Str),
 		}
 
 		for _, sdKey := range sliceData.Get("keys").Array() {
-			newSlice.Keys = append(newSlice.Keys, SliceKey{
+			key := SliceKey{
 				Frame: int32(sdKey.Get("frame").Int()),
 				X:     int(sdKey.Get("bounds.x").Int()),
 				Y:     int(sdKey.Get("bounds.y").Int()),
 				W:     int(sdKey.Get("bounds.w").Int()),
 				H:     int(sdKey.Get("bounds.h").Int()),
-			})
+			}
+
+			if center := sdKey.Get("center"); center.Exists() {
+				key.HasCenter = true
+				key.CenterX = int(center.Get("x").Int())
+				key.CenterY = int(center.Get("y").Int())
+				key.CenterW = int(center.Get("w").Int())
+				key.CenterH = int(center.Get("h").Int())
+			}
+
+			if pivot := sdKey.Get("pivot"); pivot.Exists() {
+				key.HasPivot = true
+				key.PivotX = int(pivot.Get("x").Int())
+				key.PivotY = int(pivot.Get("y").Int())
+			}
+
+			newSlice.Keys = append(newSlice.Keys, key)
 		}
 
 		f.Slices = append(f.Slices, newSlice)
 	}
 
+	f.collectEvents()
 	return nil
 }
 
+// splitLayers moves the frames of a JSON exported with "Split Layers" to the Layer they belong to, found by the name of
+// the layer in their filenames ("sprite (Layer 1) 0.aseprite"). The File's Frames are left with the frames of the first
+// layer, as every layer shares the timing.
+func (f *File) splitLayers() {
+	layerOf := make([]int, len(f.Frames))
+	matched := map[int]bool{}
+
+	for i, frame := range f.Frames {
+		best := 0
+		layerOf[i] = -1
+		for l, layer := range f.Layers {
+			if score := layerNameScore(frame.Filename, layer.Name); score > best {
+				layerOf[i], best = l, score
+			}
+		}
+
+		if layerOf[i] < 0 {
+			return
+		}
+
+		matched[layerOf[i]] = true
+	}
+
+	if len(matched) < 2 {
+		return
+	}
+
+	for i, frame := range f.Frames {
+		f.Layers[layerOf[i]].Frames = append(f.Layers[layerOf[i]].Frames, frame)
+	}
+
+	for _, layer := range f.Layers {
+		if len(layer.Frames) > 0 {
+			f.Frames = append([]Frame(nil), layer.Frames...)
+			break
+		}
+	}
+}
+
+// layerNameScore rates how well a layer name matches a frame filename, preferring the default "({layer})" format and
+// then the longest name, so "Layer 10" isn't taken for "Layer 1". It returns 0 if the name isn't in the filename.
+func layerNameScore(filename, layerName string) int {
+	switch {
+	case layerName == "" || !strings.Contains(filename, layerName):
+		return 0
+	case strings.Contains(filename, "("+layerName+")"):
+		return len(filename) + len(layerName)
+	}
+
+	return len(layerName)
+}
+
+// HasSplitLayers returns true if the File was exported with "Split Layers", having the frames of every Layer apart.
+func (f *File) HasSplitLayers() bool {
+	for _, layer := range f.Layers {
+		if len(layer.Frames) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LayerByName returns the index of the Layer that has the name specified, or -1 if it could not be found.
+func (f *File) LayerByName(layerName string) int {
+	for i, layer := range f.Layers {
+		if layer.Name == layerName {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// decodeFrame returns the Frame described by an entry of the "frames" JSON object, a shape shared by Aseprite and
+// TexturePacker.
+func decodeFrame(filename string, frameData gjson.Result) Frame {
+	frame := Frame{}
+	frame.Filename = filename
+	frame.X = int(frameData.Get("frame.x").Num)
+	frame.Y = int(frameData.Get("frame.y").Num)
+	frame.W = int(frameData.Get("frame.w").Num)
+	frame.H = int(frameData.Get("frame.h").Num)
+	frame.Duration = float32(frameData.Get("duration").Num) / 1000
+	frame.Rotated = frameData.Get("rotated").Bool()
+	frame.Trimmed = frameData.Get("trimmed").Bool()
+	frame.OffsetX = int(frameData.Get("spriteSourceSize.x").Num)
+	frame.OffsetY = int(frameData.Get("spriteSourceSize.y").Num)
+	frame.SourceW = int(frameData.Get("sourceSize.w").Num)
+	frame.SourceH = int(frameData.Get("sourceSize.h").Num)
+	frame.PivotX = frameData.Get("pivot.x").Num
+	frame.PivotY = frameData.Get("pivot.y").Num
+	return frame
+}
+
+// namedFrame is a Frame along with the name of the Tag it belongs to and its position in the Tag's sequence, for the
+// loaders that derive Tags from the names of the frames.
+type namedFrame struct {
+	Frame
+	tag   string
+	index int
+}
+
+// setNamedFrames sets the Frames of the File, sorted so the frames of every tag are contiguous and in sequence, and
+// creates a Tag for each of them along with the default ("") one. Tags keep the order in which they first appear.
+func (f *File) setNamedFrames(frames []namedFrame) {
+	rank := map[string]int{}
+	for _, frame := range frames {
+		if _, ok := rank[frame.tag]; !ok {
+			rank[frame.tag] = len(rank)
+		}
+	}
+
+	sort.SliceStable(frames, func(i, j int) bool {
+		if frames[i].tag != frames[j].tag {
+			return rank[frames[i].tag] < rank[frames[j].tag]
+		}
+
+		return frames[i].index < frames[j].index
+	})
+
+	f.Frames = nil
+	f.Tags = make(map[string]*Tag)
+
+	for i, frame := range frames {
+		f.Frames = append(f.Frames, frame.Frame)
+
+		if frame.tag == "" {
+			continue
+		}
+
+		if _, ok := f.Tags[frame.tag]; !ok {
+			f.Tags[frame.tag] = &Tag{
+				Name:      frame.tag,
+				Start:     i,
+				End:       i - 1,
+				Direction: PlayForward,
+				File:      f,
+			}
+		}
+
+		f.Tags[frame.tag].End++
+	}
+
+	f.Tags[""] = &Tag{
+		Name:      "",
+		Start:     0,
+		End:       len(f.Frames) - 1,
+		Direction: PlayForward,
+		File:      f,
+	}
+
+	if len(f.Frames) > 0 {
+		f.FrameWidth, f.FrameHeight = int32(f.Frames[0].SourceW), int32(f.Frames[0].SourceH)
+		if f.FrameWidth == 0 && f.FrameHeight == 0 {
+			f.FrameWidth, f.FrameHeight = int32(f.Frames[0].W), int32(f.Frames[0].H)
+		}
+	}
+}
+
+// splitFrameNumber splits a frame name such as "walk_0012.png" into its base name ("walk") and trailing number (12),
+// trimming the extension and any separator in between. Names without a trailing number return an index of -1.
+func splitFrameNumber(name string) (string, int) {
+	if ext := filepath.Ext(name); ext != "" {
+		if _, err := strconv.Atoi(ext[1:]); err != nil {
+			name = name[:len(name)-len(ext)]
+		}
+	}
+
+	i := len(name)
+	for i > 0 && name[i-1] >= '0' && name[i-1] <= '9' {
+		i--
+	}
+
+	index := -1
+	if i < len(name) {
+		index, _ = strconv.Atoi(name[i:])
+	}
+
+	return strings.TrimRight(name[:i], " _-."), index
+}
+
+// PageSize returns the width and height of the image of the given page. The first page of a File without Pages is
+// the one in ImagePath.
+func (f *File) PageSize(page int) (int32, int32) {
+	if page < len(f.Pages) {
+		return f.Pages[page].Width, f.Pages[page].Height
+	}
+
+	return f.Width, f.Height
+}
+
+// addPage adds a page to the File; the first one also sets the File's ImagePath, Width and Height.
+func (f *File) addPage(imagePath string, width, height int32) int {
+	if len(f.Pages) == 0 {
+		f.ImagePath, f.Width, f.Height = imagePath, width, height
+	}
+
+	f.Pages = append(f.Pages, Page{ImagePath: imagePath, Width: width, Height: height})
+	return len(f.Pages) - 1
+}
+
+// FrameSize returns the width and height of the frame at the given index, as it was before being trimmed. Frames that
+// don't carry their own size fall back to the File's FrameWidth and FrameHeight.
+func (f *File) FrameSize(index int) (int, int) {
+	frame := f.Frames[index]
+	switch {
+	case frame.SourceW != 0 || frame.SourceH != 0:
+		return frame.SourceW, frame.SourceH
+	case frame.W != 0 || frame.H != 0:
+		return frame.W, frame.H
+	}
+
+	return int(f.FrameWidth), int(f.FrameHeight)
+}
+
+// frameRect returns the area that the frame takes on the spritesheet.
+func (f *File) frameRect(frame Frame) image.Rectangle {
+	w, h := frame.W, frame.H
+	if w == 0 && h == 0 {
+		w, h = int(f.FrameWidth), int(f.FrameHeight)
+	}
+
+	if frame.Rotated {
+		w, h = h, w
+	}
+
+	return image.Rect(frame.X, frame.Y, frame.X+w, frame.Y+h)
+}
+
+// frameNumber returns the frame number of an exported frame filename, which is the number between its last space and
+// its extension ("sprite 12.aseprite" is 12).
+func frameNumber(filename string) int64 {
+	fi := strings.LastIndex(filename, " ") + 1
+	li := strings.LastIndex(filename, ".")
+	if li < fi {
+		li = len(filename)
+	}
+
+	v, _ := strconv.ParseInt(filename[fi:li], 10, 32)
+	return v
+}
+
 // SliceByName returns a Slice that has the name specified and a boolean indicating whether it could be found or not.
 // Note that a File can have multiple Slices by the same name.
 func (f *File) SliceByName(sliceName string) (Slice, bool) {
@@ -172,18 +472,30 @@ func (f *File) HasSlice(sliceName string) bool {
 	return exists
 }
 
-// Frame contains timing and position information for the frame on the spritesheet.
+// Frame contains timing and position information for the frame on the spritesheet. Packed spritesheets can trim the
+// transparent borders of a frame and rotate it to save space; OffsetX, OffsetY, SourceW and SourceH describe where the
+// trimmed frame sits within the original one.
 type Frame struct {
-	X, Y     int
-	Duration float32 // The duration of the frame in seconds.
+	Filename         string  // The filename of the frame, as exported by Aseprite; blank if the frame didn't have one.
+	X, Y             int     // The top-left corner of the frame on the spritesheet.
+	W, H             int     // The size of the frame before rotation; a rotated frame takes H x W pixels on the spritesheet.
+	Duration         float32 // The duration of the frame in seconds.
+	Rotated          bool    // Rotated is true if the frame is stored rotated 90 degrees clockwise on the spritesheet.
+	RotatedCCW       bool    // RotatedCCW is set along with Rotated if the frame was rotated counter-clockwise instead (libGDX).
+	Trimmed          bool    // Trimmed is true if the transparent borders of the frame were removed on export.
+	OffsetX, OffsetY int     // The position of the (trimmed) frame within the original frame.
+	SourceW, SourceH int     // The size of the original frame, before trimming.
+	PivotX, PivotY   float64 // The pivot point of the frame, relative to its original size (0.5, 0.5 is the center).
+	Page             int     // The index of the page (image) the frame is on, for Files with several Pages.
+	Events           []Event // The events that fire when the frame is shown.
 }
 
 // Slice represents a Slice (rectangle) that was defined in Aseprite and exported in the JSON file.
 type Slice struct {
 	Name  string     // Name is the name of the Slice, as specified in Aseprite.
-	Data  string     // Data is blank by default, but can be specified on export from Aseprite to be whatever you need it to be.
+	Data  UserData   // Data is blank by default, but can be specified on export from Aseprite to be whatever you need it to be.
 	Keys  []SliceKey // The individual keys (positions and sizes of Slices) according to the Frames they operate on.
-	Color int64
+	Color int64      // Color is the color of the Slice in Aseprite, as 0xRRGGBBAA.
 }
 
 // SliceKey represents a Slice's size and position in the Aseprite file on a specific frame. An individual Aseprite File can have multiple
@@ -192,6 +504,15 @@ type Slice struct {
 type SliceKey struct {
 	Frame      int32
 	X, Y, W, H int
+
+	// HasCenter is true if the Slice is a 9-slice; CenterX, CenterY, CenterW and CenterH are then its inner rectangle,
+	// relative to the key's bounds.
+	HasCenter                          bool
+	CenterX, CenterY, CenterW, CenterH int
+
+	// HasPivot is true if the Slice has a pivot; PivotX and PivotY are then its position, relative to the key's bounds.
+	HasPivot       bool
+	PivotX, PivotY int
 }
 
 // Center returns the center X and Y position of the Slice in the current key.
@@ -201,17 +522,44 @@ func (k SliceKey) Center() (int, int) {
 
 // Tag contains details regarding each tag or animation from Aseprite.
 // Start and End are the starting and ending frame of the Tag. Direction is a string, and can be assigned one of the playback constants.
+// Repeat is the number of times the Tag plays before finishing, where each way of a ping-pong counts as one; 0 plays it forever.
 type Tag struct {
 	Name       string
 	Start, End int
 	Direction  Direction
+	Repeat     int
+	Data       UserData // The user data of the Tag.
+	Color      int64    // The color of the Tag in Aseprite, as 0xRRGGBBAA.
 	File       *File
 }
 
 // Layer contains details regarding the layers exported from Aseprite, including the layer's name (string), opacity (0-255), and
-// blend mode (string).
+// blend mode. Frames holds the frames of the layer if the File was exported with "Split Layers"; it's empty
+// otherwise.
 type Layer struct {
 	Name      string
 	Opacity   uint8
-	BlendMode string
+	BlendMode BlendMode
+	Frames    []Frame
+	Data      UserData // The user data of the Layer.
+	Color     int64    // The color of the Layer in Aseprite, as 0xRRGGBBAA.
+	Cels      []Cel    // The cels of the Layer that have user data.
+}
+
+// Cel is the user data of the cel (the contents of a Layer on a frame) on the given Frame.
+type Cel struct {
+	Frame int
+	Data  UserData
+	Color int64 // The color of the cel in Aseprite, as 0xRRGGBBAA.
+}
+
+// CelAt returns the Cel of the Layer on the given frame, and false if it has no user data.
+func (l Layer) CelAt(frame int) (Cel, bool) {
+	for _, cel := range l.Cels {
+		if cel.Frame == frame {
+			return cel, true
+		}
+	}
+
+	return Cel{}, false
 }
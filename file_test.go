@@ -0,0 +1,109 @@
+package sprite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadAsepriteArray(t *testing.T) {
+	hash, err := ReadAseprite([]byte(asepriteHash))
+	require.NoError(t, err)
+
+	array, err := ReadAseprite([]byte(asepriteArray))
+	require.NoError(t, err)
+
+	require.Len(t, array.Frames, 3)
+	assert.Equal(t, hash.Frames, array.Frames)
+	assert.Equal(t, array.Frames[2].Filename, "walk 10.aseprite")
+	assert.Equal(t, array.Frames[2].X, 32)
+	assert.Equal(t, array.Frames[2].Duration, float32(0.3))
+	assert.Equal(t, array.FrameWidth, int32(16))
+}
+
+var asepriteHash = `{ "frames": {
+	"walk 10.aseprite": { "frame": { "x": 32, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 300 },
+	"walk 2.aseprite": { "frame": { "x": 16, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 200 },
+	"walk 1.aseprite": { "frame": { "x": 0, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 100 }
+ },
+ "meta": { "image": "walk.png", "size": { "w": 48, "h": 16 } }
+}`
+
+var asepriteArray = `{ "frames": [
+	{ "filename": "walk 1.aseprite", "frame": { "x": 0, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 100 },
+	{ "filename": "walk 2.aseprite", "frame": { "x": 16, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 200 },
+	{ "filename": "walk 10.aseprite", "frame": { "x": 32, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 300 }
+ ],
+ "meta": { "image": "walk.png", "size": { "w": 48, "h": 16 } }
+}`
+
+func TestReadAsepriteTrimmedRotated(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteTrimmed))
+	require.NoError(t, err)
+
+	require.Len(t, f.Frames, 2)
+	assert.Equal(t, f.Frames[0], Frame{
+		Filename: "hero 0.png", X: 2, Y: 4, W: 10, H: 12, Duration: 0.1,
+		Trimmed: true, OffsetX: 3, OffsetY: 1, SourceW: 16, SourceH: 16,
+	})
+	assert.True(t, f.Frames[1].Rotated)
+	assert.Equal(t, f.Frames[1].W, 6)
+	assert.Equal(t, f.Frames[1].H, 14)
+}
+
+var asepriteTrimmed = `{ "frames": [
+	{
+		"filename": "hero 0.png", "frame": { "x": 2, "y": 4, "w": 10, "h": 12 }, "rotated": false, "trimmed": true,
+		"spriteSourceSize": { "x": 3, "y": 1, "w": 10, "h": 12 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 100
+	},
+	{
+		"filename": "hero 1.png", "frame": { "x": 14, "y": 4, "w": 6, "h": 14 }, "rotated": true, "trimmed": true,
+		"spriteSourceSize": { "x": 5, "y": 2, "w": 6, "h": 14 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 100
+	}
+ ],
+ "meta": { "image": "hero.png", "size": { "w": 32, "h": 32 } }
+}`
+
+func TestReadAsepriteSplitLayers(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteSplitLayers))
+	require.NoError(t, err)
+
+	assert.True(t, f.HasSplitLayers())
+	require.Len(t, f.Layers, 3)
+	require.Len(t, f.Frames, 2)
+	assert.Equal(t, f.Frames[0].Filename, "hero (Layer 1) 0.aseprite")
+	assert.Equal(t, f.Frames[1].Filename, "hero (Layer 1) 1.aseprite")
+	assert.Equal(t, f.Tags[""].End, 1)
+
+	require.Len(t, f.Layers[0].Frames, 2)
+	assert.Equal(t, f.Layers[0].Frames[1].X, 16)
+	require.Len(t, f.Layers[1].Frames, 2)
+	assert.Equal(t, f.Layers[1].Frames[0].Filename, "hero (Layer 10) 0.aseprite")
+	assert.Equal(t, f.Layers[1].Frames[1].X, 48)
+	assert.Empty(t, f.Layers[2].Frames)
+
+	assert.Equal(t, f.Layers[1].BlendMode, BlendAddition)
+	assert.Equal(t, f.LayerByName("Layer 10"), 1)
+	assert.Equal(t, f.LayerByName("Layer 2"), -1)
+
+	plain, err := ReadAseprite([]byte(asepriteHash))
+	require.NoError(t, err)
+	assert.False(t, plain.HasSplitLayers())
+}
+
+var asepriteSplitLayers = `{ "frames": {
+	"hero (Layer 10) 1.aseprite": { "frame": { "x": 48, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 100 },
+	"hero (Layer 1) 1.aseprite": { "frame": { "x": 16, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 100 },
+	"hero (Layer 10) 0.aseprite": { "frame": { "x": 32, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 100 },
+	"hero (Layer 1) 0.aseprite": { "frame": { "x": 0, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 100 }
+ },
+ "meta": {
+	"image": "hero.png", "size": { "w": 64, "h": 16 },
+	"layers": [
+		{ "name": "Layer 1", "opacity": 255, "blendMode": "normal" },
+		{ "name": "Layer 10", "opacity": 128, "blendMode": "addition" },
+		{ "name": "Hat", "opacity": 255, "blendMode": "normal" }
+	]
+ }
+}`
@@ -0,0 +1,112 @@
+package sprite
+
+import (
+	"image"
+	"image/draw"
+	"image/gif"
+	"io"
+	"os"
+)
+
+// gifDefaultDelay is the duration used for frames without delay, which browsers play at 100ms.
+const gifDefaultDelay = 0.1
+
+// OpenGIF will use os.Open() to read the animated GIF file path specified. It returns a *File, along with an atlas
+// image containing every frame of the animation. Files created with OpenGIF() will put the filepath used in the Path
+// field.
+func OpenGIF(path string) (*File, *image.RGBA, error) {
+	r, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer r.Close()
+
+	f, img, err := ReadGIF(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f.Path = path
+	return f, img, nil
+}
+
+// ReadGIF returns a *File and an atlas image for a given animated GIF. Frames are composited as a browser would,
+// honoring their disposal methods, so every frame on the atlas is a full image even if the GIF only stored the
+// changes from the previous one. The delay of every frame is used as its Duration.
+func ReadGIF(r io.Reader) (*File, *image.RGBA, error) {
+	g, err := gif.DecodeAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	if bounds.Empty() {
+		for _, frame := range g.Image {
+			bounds = bounds.Union(frame.Bounds())
+		}
+	}
+
+	w, h := bounds.Dx(), bounds.Dy()
+	atlas, positions := newAtlas(len(g.Image), w, h)
+
+	f := &File{
+		Width:       int32(atlas.Bounds().Dx()),
+		Height:      int32(atlas.Bounds().Dy()),
+		FrameWidth:  int32(w),
+		FrameHeight: int32(h),
+		Tags:        make(map[string]*Tag),
+	}
+
+	canvas := image.NewRGBA(bounds)
+	var previous *image.RGBA
+	for i, frame := range g.Image {
+		disposal := byte(gif.DisposalNone)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+
+		if disposal == gif.DisposalPrevious {
+			previous = image.NewRGBA(bounds)
+			draw.Draw(previous, bounds, canvas, bounds.Min, draw.Src)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+
+		pos := positions[i]
+		draw.Draw(atlas, image.Rect(pos.X, pos.Y, pos.X+w, pos.Y+h), canvas, bounds.Min, draw.Src)
+
+		duration := float32(gifDefaultDelay)
+		if i < len(g.Delay) && g.Delay[i] > 0 {
+			duration = float32(g.Delay[i]) / 100
+		}
+
+		f.Frames = append(f.Frames, Frame{
+			X:        pos.X,
+			Y:        pos.Y,
+			W:        w,
+			H:        h,
+			Duration: duration,
+			SourceW:  w,
+			SourceH:  h,
+		})
+
+		// The disposal method of a frame is applied once it's been displayed, before drawing the next one.
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+
+	f.Tags[""] = &Tag{
+		Name:      "",
+		Start:     0,
+		End:       len(f.Frames) - 1,
+		Direction: PlayForward,
+		File:      f,
+	}
+
+	return f, atlas, nil
+}
@@ -0,0 +1,69 @@
+package sprite
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadGIF(t *testing.T) {
+	palette := color.Palette{color.Transparent, color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}}
+
+	// A red background, a blue square that is cleared afterwards and a blue square that is reverted afterwards.
+	background := image.NewPaletted(image.Rect(0, 0, 4, 4), palette)
+	for i := range background.Pix {
+		background.Pix[i] = 1
+	}
+
+	cleared := image.NewPaletted(image.Rect(0, 0, 2, 2), palette)
+	reverted := image.NewPaletted(image.Rect(2, 2, 4, 4), palette)
+	for i := range cleared.Pix {
+		cleared.Pix[i] = 2
+		reverted.Pix[i] = 2
+	}
+
+	last := image.NewPaletted(image.Rect(3, 0, 4, 1), palette)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, gif.EncodeAll(buf, &gif.GIF{
+		Image:    []*image.Paletted{background, cleared, reverted, last},
+		Delay:    []int{10, 0, 25, 5},
+		Disposal: []byte{gif.DisposalNone, gif.DisposalBackground, gif.DisposalPrevious, gif.DisposalNone},
+		Config:   image.Config{ColorModel: palette, Width: 4, Height: 4},
+	}))
+
+	f, img, err := ReadGIF(buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, f.FrameWidth, int32(4))
+	assert.Equal(t, f.Width, int32(8))
+	assert.Equal(t, f.Height, int32(8))
+	assert.Equal(t, f.Tags[""].End, 3)
+
+	require.Len(t, f.Frames, 4)
+	assert.Equal(t, f.Frames[0].Duration, float32(0.1))
+	assert.Equal(t, f.Frames[1].Duration, float32(gifDefaultDelay))
+	assert.Equal(t, f.Frames[2].Duration, float32(0.25))
+
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	at := func(frame, x, y int) color.Color {
+		return img.At(f.Frames[frame].X+x, f.Frames[frame].Y+y)
+	}
+
+	assert.Equal(t, red, at(0, 0, 0))
+	assert.Equal(t, blue, at(1, 0, 0))
+	assert.Equal(t, red, at(1, 3, 3))
+
+	// The blue square of frame 1 was cleared, and the one of frame 2 reverted.
+	assert.Equal(t, color.RGBA{}, at(2, 0, 0))
+	assert.Equal(t, blue, at(2, 3, 3))
+	assert.Equal(t, color.RGBA{}, at(3, 0, 0))
+	assert.Equal(t, red, at(3, 3, 3))
+	assert.Equal(t, red, at(3, 3, 0))
+}
@@ -0,0 +1,155 @@
+package sprite
+
+import (
+	"errors"
+	"image"
+	"os"
+	"path/filepath"
+
+	_ "image/png"
+)
+
+var (
+	ErrInvalidGrid = errors.New("invalid grid")
+)
+
+// GridOptions configures how a uniform grid of frames is laid out on an image.
+type GridOptions struct {
+	Margin      int       // Margin is the space around the grid, in pixels.
+	Spacing     int       // Spacing is the space between two frames, in pixels.
+	ColumnMajor bool      // ColumnMajor orders the frames top to bottom and then left to right, instead of row by row.
+	Count       int       // Count limits the number of frames; 0 uses every frame that fits on the image.
+	Duration    float32   // Duration is the duration in seconds of every frame; 0 uses 100ms, as Aseprite does.
+	Rows        []string  // Rows names a Tag after each row of frames (or column, if ColumnMajor); "" leaves a row untagged.
+	Tags        []GridTag // Tags are Tags over arbitrary ranges of frames, which have to be within the grid.
+}
+
+// GridTag is a Tag over a range of frames of a grid, from Start to End (both included).
+type GridTag struct {
+	Name       string
+	Start, End int
+	Direction  Direction
+}
+
+// OpenGrid returns a *File for an image split into a uniform grid of frames of frameW x frameH pixels, reading just
+// the size of the image from imagePath. opts can be nil to use every frame, with no margin or spacing, row by row.
+// The image has to be in a format registered in the image package; PNG is always available.
+func OpenGrid(imagePath string, frameW, frameH int, opts *GridOptions) (*File, error) {
+	r, err := os.Open(imagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := NewGrid(cfg.Width, cfg.Height, frameW, frameH, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	f.Path = imagePath
+	f.ImagePath = filepath.Clean(imagePath)
+	return f, nil
+}
+
+// NewGrid returns a *File for an image of width x height pixels split into a uniform grid of frames of frameW x frameH
+// pixels. It's the same as OpenGrid, for images that are already loaded. It returns ErrInvalidGrid if the frame size isn't
+// positive, the Duration is negative or a GridTag has frames that aren't on the grid.
+func NewGrid(width, height, frameW, frameH int, opts *GridOptions) (*File, error) {
+	if opts == nil {
+		opts = &GridOptions{}
+	}
+
+	if frameW <= 0 || frameH <= 0 || opts.Duration < 0 {
+		return nil, ErrInvalidGrid
+	}
+
+	duration := opts.Duration
+	if duration == 0 {
+		duration = 0.1
+	}
+
+	cols := (width - opts.Margin*2 + opts.Spacing) / (frameW + opts.Spacing)
+	rows := (height - opts.Margin*2 + opts.Spacing) / (frameH + opts.Spacing)
+	if cols < 0 || rows < 0 {
+		cols, rows = 0, 0
+	}
+
+	count := cols * rows
+	if opts.Count > 0 && opts.Count < count {
+		count = opts.Count
+	}
+
+	// perLine is the number of frames on every row (or column) in the order the frames are numbered.
+	perLine := cols
+	if opts.ColumnMajor {
+		perLine = rows
+	}
+
+	f := &File{
+		Width:       int32(width),
+		Height:      int32(height),
+		FrameWidth:  int32(frameW),
+		FrameHeight: int32(frameH),
+		Tags:        make(map[string]*Tag),
+	}
+
+	for i := 0; i < count; i++ {
+		col, row := i%perLine, i/perLine
+		if opts.ColumnMajor {
+			col, row = row, col
+		}
+
+		f.Frames = append(f.Frames, Frame{
+			X:        opts.Margin + col*(frameW+opts.Spacing),
+			Y:        opts.Margin + row*(frameH+opts.Spacing),
+			W:        frameW,
+			H:        frameH,
+			Duration: duration,
+			SourceW:  frameW,
+			SourceH:  frameH,
+		})
+	}
+
+	for i, name := range opts.Rows {
+		start := i * perLine
+		if name == "" || start >= count {
+			continue
+		}
+
+		end := start + perLine - 1
+		if end >= count {
+			end = count - 1
+		}
+
+		f.Tags[name] = &Tag{Name: name, Start: start, End: end, Direction: PlayForward, File: f}
+	}
+
+	for _, t := range opts.Tags {
+		if t.Start < 0 || t.End >= count || t.Start > t.End {
+			return nil, ErrInvalidGrid
+		}
+
+		direction := t.Direction
+		if direction == "" {
+			direction = PlayForward
+		}
+
+		f.Tags[t.Name] = &Tag{Name: t.Name, Start: t.Start, End: t.End, Direction: direction, File: f}
+	}
+
+	f.Tags[""] = &Tag{
+		Name:      "",
+		Start:     0,
+		End:       len(f.Frames) - 1,
+		Direction: PlayForward,
+		File:      f,
+	}
+
+	return f, nil
+}
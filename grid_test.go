@@ -0,0 +1,90 @@
+package sprite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGrid(t *testing.T) {
+	f, err := NewGrid(100, 60, 16, 16, &GridOptions{
+		Margin:   2,
+		Spacing:  4,
+		Count:    14,
+		Duration: 0.1,
+		Rows:     []string{"idle", "", "jump"},
+		Tags:     []GridTag{{Name: "blink", Start: 3, End: 4, Direction: PlayPingPong}},
+	})
+	require.NoError(t, err)
+
+	// (100 - 4 + 4) / 20 = 5 columns, (60 - 4 + 4) / 20 = 3 rows.
+	require.Len(t, f.Frames, 14)
+	assert.Equal(t, f.Frames[1].X, 22)
+	assert.Equal(t, f.Frames[1].Y, 2)
+	assert.Equal(t, f.Frames[5].X, 2)
+	assert.Equal(t, f.Frames[5].Y, 22)
+	assert.Equal(t, f.Frames[13].X, 62)
+	assert.Equal(t, f.Frames[13].Y, 42)
+	assert.Equal(t, f.Frames[0].Duration, float32(0.1))
+
+	require.Len(t, f.Tags, 4)
+	assert.Equal(t, f.Tags["idle"].Start, 0)
+	assert.Equal(t, f.Tags["idle"].End, 4)
+	assert.Equal(t, f.Tags["jump"].Start, 10)
+	assert.Equal(t, f.Tags["jump"].End, 13)
+	assert.Equal(t, f.Tags["blink"].Direction, PlayPingPong)
+	assert.Equal(t, f.Tags[""].End, 13)
+
+	p := f.CreatePlayer()
+	require.NoError(t, p.Play("jump"))
+	x1, y1, x2, y2 := p.CurrentFrameCoords()
+	assert.Equal(t, []int{2, 42, 18, 58}, []int{x1, y1, x2, y2})
+}
+
+func TestNewGridColumnMajor(t *testing.T) {
+	f, err := NewGrid(64, 32, 16, 16, &GridOptions{ColumnMajor: true, Rows: []string{"a", "b"}})
+	require.NoError(t, err)
+
+	require.Len(t, f.Frames, 8)
+	assert.Equal(t, f.Frames[1].X, 0)
+	assert.Equal(t, f.Frames[1].Y, 16)
+	assert.Equal(t, f.Frames[2].X, 16)
+	assert.Equal(t, f.Frames[2].Y, 0)
+	assert.Equal(t, f.Tags["b"].Start, 2)
+	assert.Equal(t, f.Tags["b"].End, 3)
+}
+
+func TestOpenGrid(t *testing.T) {
+	f, err := OpenGrid("example/16x16Deliveryman.png", 16, 16, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, f.ImagePath, "example/16x16Deliveryman.png")
+	assert.Len(t, f.Frames, 6)
+
+	_, err = OpenGrid("example/16x16Deliveryman.png", 0, 16, nil)
+	assert.ErrorIs(t, err, ErrInvalidGrid)
+}
+
+func TestNewGridDefaults(t *testing.T) {
+	f, err := NewGrid(64, 16, 16, 16, nil)
+	require.NoError(t, err)
+	assert.Equal(t, f.Frames[0].Duration, float32(0.1))
+
+	p := f.CreatePlayer()
+	require.NoError(t, p.Play(""))
+	p.Update(0.25)
+	assert.Equal(t, p.FrameIndex, 2)
+}
+
+func TestNewGridInvalid(t *testing.T) {
+	for _, opts := range []*GridOptions{
+		{Duration: -1},
+		{Tags: []GridTag{{Name: "a", Start: -1, End: 2}}},
+		{Tags: []GridTag{{Name: "a", Start: 2, End: 4}}},
+		{Tags: []GridTag{{Name: "a", Start: 3, End: 2}}},
+	} {
+		_, err := NewGrid(64, 16, 16, 16, opts)
+		assert.ErrorIs(t, err, ErrInvalidGrid)
+	}
+}
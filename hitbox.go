@@ -0,0 +1,146 @@
+package sprite
+
+import (
+	"strings"
+)
+
+// BoxKind is the kind of a collision box authored as a Slice.
+type BoxKind int
+
+const (
+	BoxNone BoxKind = iota // The Slice isn't a collision box.
+	BoxHit                 // The Slice deals damage: named "hit:name" or with "hit" as its data.
+	BoxHurt                // The Slice receives damage: named "hurt:name" or with "hurt" as its data.
+)
+
+// boxPrefixes are the prefixes of the names of the Slices that are collision boxes, by kind.
+var boxPrefixes = map[BoxKind]string{
+	BoxHit:  "hit:",
+	BoxHurt: "hurt:",
+}
+
+// BoxKind returns the kind of collision box the Slice is and its name without the prefix. Slices are classified by
+// the prefix of their name ("hit:punch", "hurt:body") or, if they don't have one, by their Data being "hit" or "hurt".
+func (s Slice) BoxKind() (BoxKind, string) {
+	for kind, prefix := range boxPrefixes {
+		if strings.HasPrefix(s.Name, prefix) {
+			return kind, strings.TrimPrefix(s.Name, prefix)
+		}
+	}
+
+	switch strings.TrimSpace(string(s.Data)) {
+	case "hit":
+		return BoxHit, s.Name
+	case "hurt":
+		return BoxHurt, s.Name
+	}
+
+	return BoxNone, s.Name
+}
+
+// Transform places a Player in the world to get its collision boxes. The sprite is flipped within its frame, then
+// scaled and then moved to X, Y, the same as drawing it with a GeoM that does so.
+type Transform struct {
+	X, Y           float64 // The position of the top-left corner of the frame in the world.
+	ScaleX, ScaleY float64 // The scale of the sprite; 0 is the same as 1.
+	FlipX, FlipY   bool    // Whether the sprite is mirrored horizontally or vertically.
+}
+
+// Box is a collision box of a Player in world space.
+type Box struct {
+	Name       string  // The name of the box, without the "hit:" or "hurt:" prefix.
+	Kind       BoxKind // The kind of the box.
+	Slice      string  // The name of the Slice the box comes from.
+	X, Y, W, H float64 // The bounds of the box in the world.
+}
+
+// Intersects returns true if both boxes overlap. Boxes that only touch on an edge don't.
+func (b Box) Intersects(o Box) bool {
+	return b.X < o.X+o.W && o.X < b.X+b.W && b.Y < o.Y+o.H && o.Y < b.Y+b.H
+}
+
+// Contact is a pair of boxes that overlap: a hit box of a Player and a hurt box of another.
+type Contact struct {
+	Hit, Hurt Box
+}
+
+// Boxes returns the collision boxes of the given kind that are active on the current frame, in world space
+// according to the Transform. BoxNone returns both the hit and the hurt boxes. A Slice is active on a frame if it has
+// a key on it that isn't empty. If InterpolateSlices is set, boxes move smoothly between keys.
+func (p *Player) Boxes(kind BoxKind, tr Transform) []Box {
+	if p.CurrentTag == nil {
+		return nil
+	}
+
+	frameW, frameH := p.CurrentFrameSize()
+	scaleX, scaleY := tr.ScaleX, tr.ScaleY
+	if scaleX == 0 {
+		scaleX = 1
+	}
+	if scaleY == 0 {
+		scaleY = 1
+	}
+
+	var boxes []Box
+	for _, slice := range p.File.Slices {
+		boxKind, name := slice.BoxKind()
+		if boxKind == BoxNone || kind != BoxNone && boxKind != kind {
+			continue
+		}
+
+		key, ok := p.sliceKey(slice)
+		if !ok || key.W <= 0 || key.H <= 0 {
+			continue
+		}
+
+		x, y := float64(key.X), float64(key.Y)
+		if tr.FlipX {
+			x = float64(frameW - key.X - key.W)
+		}
+		if tr.FlipY {
+			y = float64(frameH - key.Y - key.H)
+		}
+
+		box := Box{
+			Name:  name,
+			Kind:  boxKind,
+			Slice: slice.Name,
+			X:     tr.X + x*scaleX,
+			Y:     tr.Y + y*scaleY,
+			W:     float64(key.W) * scaleX,
+			H:     float64(key.H) * scaleY,
+		}
+
+		// Negative scales mirror the box around the position.
+		if box.W < 0 {
+			box.X, box.W = box.X+box.W, -box.W
+		}
+		if box.H < 0 {
+			box.Y, box.H = box.Y+box.H, -box.H
+		}
+
+		boxes = append(boxes, box)
+	}
+
+	return boxes
+}
+
+// Contacts returns the hit boxes of the Player that overlap the hurt boxes of the other one, with both placed in the
+// world by their Transforms.
+func (p *Player) Contacts(tr Transform, other *Player, otherTr Transform) []Contact {
+	hurts := other.Boxes(BoxHurt, otherTr)
+	if len(hurts) == 0 {
+		return nil
+	}
+
+	var contacts []Contact
+	for _, hit := range p.Boxes(BoxHit, tr) {
+		for _, hurt := range hurts {
+			if hit.Intersects(hurt) {
+				contacts = append(contacts, Contact{Hit: hit, Hurt: hurt})
+			}
+		}
+	}
+
+	return contacts
+}
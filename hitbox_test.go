@@ -0,0 +1,87 @@
+package sprite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var asepriteBoxes = `{ "frames": {
+	"fighter 0.aseprite": { "frame": { "x": 0, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 100 },
+	"fighter 1.aseprite": { "frame": { "x": 16, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 100 }
+ },
+ "meta": {
+	"image": "fighter.png", "size": { "w": 32, "h": 16 },
+	"slices": [
+		{ "name": "hit:punch", "color": "#ff0000ff", "keys": [
+			{ "frame": 0, "bounds": { "x": 10, "y": 4, "w": 6, "h": 4 } },
+			{ "frame": 1, "bounds": { "x": 0, "y": 0, "w": 0, "h": 0 } }
+		] },
+		{ "name": "hurt:body", "color": "#00ff00ff", "keys": [ { "frame": 0, "bounds": { "x": 4, "y": 2, "w": 8, "h": 14 } } ] },
+		{ "name": "feet", "data": "hurt", "color": "#00ff00ff", "keys": [ { "frame": 0, "bounds": { "x": 4, "y": 14, "w": 8, "h": 2 } } ] },
+		{ "name": "label", "color": "#0000ffff", "keys": [ { "frame": 0, "bounds": { "x": 0, "y": 0, "w": 16, "h": 4 } } ] }
+	]
+ }
+}`
+
+func TestSliceBoxKind(t *testing.T) {
+	kind, name := Slice{Name: "hit:punch"}.BoxKind()
+	assert.Equal(t, kind, BoxHit)
+	assert.Equal(t, name, "punch")
+
+	kind, name = Slice{Name: "hurt:body"}.BoxKind()
+	assert.Equal(t, kind, BoxHurt)
+	assert.Equal(t, name, "body")
+
+	kind, name = Slice{Name: "feet", Data: "hurt"}.BoxKind()
+	assert.Equal(t, kind, BoxHurt)
+	assert.Equal(t, name, "feet")
+
+	kind, _ = Slice{Name: "label"}.BoxKind()
+	assert.Equal(t, kind, BoxNone)
+}
+
+func TestPlayerBoxes(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteBoxes))
+	require.NoError(t, err)
+
+	p := f.CreatePlayer()
+	require.NoError(t, p.Play(""))
+
+	assert.Len(t, p.Boxes(BoxNone, Transform{}), 3)
+	assert.Len(t, p.Boxes(BoxHurt, Transform{}), 2)
+
+	hits := p.Boxes(BoxHit, Transform{X: 100, Y: 50, ScaleX: 2, ScaleY: 2})
+	require.Len(t, hits, 1)
+	assert.Equal(t, hits[0], Box{Name: "punch", Kind: BoxHit, Slice: "hit:punch", X: 120, Y: 58, W: 12, H: 8})
+
+	hits = p.Boxes(BoxHit, Transform{X: 100, FlipX: true})
+	require.Len(t, hits, 1)
+	assert.Equal(t, []float64{100, 4, 6, 4}, []float64{hits[0].X, hits[0].Y, hits[0].W, hits[0].H})
+
+	hits = p.Boxes(BoxHit, Transform{X: 100, ScaleX: -1})
+	require.Len(t, hits, 1)
+	assert.Equal(t, []float64{84, 4, 6, 4}, []float64{hits[0].X, hits[0].Y, hits[0].W, hits[0].H})
+
+	// The punch has an empty key on the second frame.
+	p.SetFrameIndex(1)
+	assert.Empty(t, p.Boxes(BoxHit, Transform{}))
+}
+
+func TestPlayerContacts(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteBoxes))
+	require.NoError(t, err)
+
+	a, b := f.CreatePlayer(), f.CreatePlayer()
+	require.NoError(t, a.Play(""))
+	require.NoError(t, b.Play(""))
+
+	// The punch (10..16) reaches the body of the other one (x+4..x+12), facing it.
+	contacts := a.Contacts(Transform{}, b, Transform{X: 8, FlipX: true})
+	require.Len(t, contacts, 1)
+	assert.Equal(t, contacts[0].Hit.Name, "punch")
+	assert.Equal(t, contacts[0].Hurt.Name, "body")
+
+	assert.Empty(t, a.Contacts(Transform{}, b, Transform{X: 12}))
+}
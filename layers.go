@@ -0,0 +1,145 @@
+package sprite
+
+import (
+	"errors"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var (
+	ErrNoLayerByName = errors.New("no layers by name")
+)
+
+// layerState is the visibility and opacity of a Layer at runtime, for a single Player.
+type layerState struct {
+	hidden  bool
+	opacity uint8
+}
+
+// layerState returns the runtime state of the Layer at the given index, initializing the states from the File's
+// Layers the first time.
+func (p *Player) layerState(index int) *layerState {
+	if len(p.layers) != len(p.File.Layers) {
+		p.layers = make([]layerState, len(p.File.Layers))
+		for i, layer := range p.File.Layers {
+			p.layers[i].opacity = layer.Opacity
+		}
+	}
+
+	return &p.layers[index]
+}
+
+// SetLayerVisible shows or hides the Layer with the given name when drawing a File exported with "Split Layers".
+func (p *Player) SetLayerVisible(layerName string, visible bool) error {
+	i := p.File.LayerByName(layerName)
+	if i < 0 {
+		return ErrNoLayerByName
+	}
+
+	p.layerState(i).hidden = !visible
+	return nil
+}
+
+// LayerVisible returns true if the Layer with the given name exists and is visible.
+func (p *Player) LayerVisible(layerName string) bool {
+	i := p.File.LayerByName(layerName)
+	return i >= 0 && !p.layerState(i).hidden
+}
+
+// SetLayerOpacity sets the opacity (0-255) of the Layer with the given name when drawing a File exported with
+// "Split Layers", replacing the one the Layer was exported with.
+func (p *Player) SetLayerOpacity(layerName string, opacity uint8) error {
+	i := p.File.LayerByName(layerName)
+	if i < 0 {
+		return ErrNoLayerByName
+	}
+
+	p.layerState(i).opacity = opacity
+	return nil
+}
+
+// LayerOpacity returns the current opacity (0-255) of the Layer with the given name, or 0 if it doesn't exist.
+func (p *Player) LayerOpacity(layerName string) uint8 {
+	i := p.File.LayerByName(layerName)
+	if i < 0 {
+		return 0
+	}
+
+	return p.layerState(i).opacity
+}
+
+// resizeCanvas makes sure the Player's canvas is w x h pixels, creating it again if it isn't.
+func (p *Player) resizeCanvas(w, h int) {
+	if p.canvas == nil || p.canvas.Bounds().Dx() != w || p.canvas.Bounds().Dy() != h {
+		p.canvas = ebiten.NewImage(w, h)
+		p.layerImg = nil
+		p.backdrop = nil
+	}
+}
+
+// drawLayers composites the visible layers of the current frame, bottom to top, on the Player's canvas and returns it.
+// Layers are blended with their BlendMode, using a shader for the modes that ebiten can't blend by itself.
+func (p *Player) drawLayers() (*ebiten.Image, error) {
+	w, h := p.File.FrameSize(p.FrameIndex)
+	if w <= 0 || h <= 0 {
+		return nil, nil
+	}
+
+	p.resizeCanvas(w, h)
+	p.canvas.Clear()
+
+	for i, layer := range p.File.Layers {
+		state := p.layerState(i)
+		if state.hidden || state.opacity == 0 || p.FrameIndex >= len(layer.Frames) {
+			continue
+		}
+
+		frame := layer.Frames[p.FrameIndex]
+		img := p.frameImage(frame)
+		if img == nil {
+			continue
+		}
+
+		opacity := float32(state.opacity) / 255
+
+		if blend, ok := layer.BlendMode.ebitenBlend(); ok {
+			opts := &ebiten.DrawImageOptions{}
+			opts.GeoM = frameGeoM(frame)
+			opts.ColorScale.ScaleAlpha(opacity)
+			opts.Blend = blend
+			p.canvas.DrawImage(img, opts)
+			continue
+		}
+
+		shader, err := getBlendShader()
+		if err != nil {
+			return nil, err
+		}
+
+		// The shader needs the layer and the backdrop as images of the same size, and can't read the destination.
+		if p.layerImg == nil {
+			p.layerImg = ebiten.NewImage(w, h)
+			p.backdrop = ebiten.NewImage(w, h)
+		}
+
+		p.layerImg.Clear()
+		opts := &ebiten.DrawImageOptions{}
+		opts.GeoM = frameGeoM(frame)
+		p.layerImg.DrawImage(img, opts)
+
+		p.backdrop.Clear()
+		p.backdrop.DrawImage(p.canvas, &ebiten.DrawImageOptions{Blend: ebiten.BlendCopy})
+
+		shaderOpts := &ebiten.DrawRectShaderOptions{}
+		shaderOpts.Images[0] = p.layerImg
+		shaderOpts.Images[1] = p.backdrop
+		shaderOpts.Uniforms = map[string]interface{}{
+			"Mode":    int(layer.BlendMode),
+			"Opacity": opacity,
+		}
+		shaderOpts.Blend = ebiten.BlendCopy
+		p.canvas.DrawRectShader(w, h, shader, shaderOpts)
+	}
+
+	return p.canvas, nil
+}
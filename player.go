@@ -3,7 +3,7 @@ package sprite
 
 import (
 	"errors"
-	"image"
+	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -24,6 +24,12 @@ type Player struct {
 	prevUVX float64
 	prevUVY float64
 
+	// InterpolateSlices makes CurrentSlice interpolate the bounds of Slices between their keys, for smooth motion.
+	InterpolateSlices bool
+
+	// The callbacks below act as a single subscriber with priority 0, called before the other subscribers with the same
+	// priority; see Subscribe to add more.
+
 	// OnLoop gets called when the playing animation / tag does a complete loop. For a ping-pong
 	// animation, this is a full forward + back cycle.
 	OnLoop func(p *Player)
@@ -34,12 +40,33 @@ type Player struct {
 	// through another tag).
 	OnTagEnter func(p *Player, t *Tag)
 	OnTagExit  func(p *Player, t *Tag)
+	// OnFinish gets called when the playing animation / tag finishes, after playing as many times as its Repeat.
+	OnFinish func(p *Player)
+	// OnQueueNext gets called when the Player starts playing the next animation / tag in its queue.
+	OnQueueNext func(p *Player, t *Tag)
+	// OnEvent gets called for each of the Events of a frame when the frame is shown, even if it's shown for less than
+	// an update.
+	OnEvent func(p *Player, name string, payload UserData)
 
 	// OnDraw callbacl called just before drawing the sprite, if return false the draw is aborted.
 	OnDraw func(p *Player, screen, img *ebiten.Image, opts *ebiten.DrawImageOptions) bool
 
 	playDirection int
-	img           *ebiten.Image
+	passes        int  // The number of times the playing tag has been played through, each way of a ping-pong counting as one.
+	step          int  // The position of the current frame in the timeline of the playing tag.
+	finished      bool // Whether the playing tag finished playing all its repeats.
+	options       PlayOptions
+	prevTag       *Tag // The tag played before the current one, to return to with PlayModeOnceReturn.
+	prevOptions   PlayOptions
+	queue         []queueEntry
+	fade          *Crossfade
+	listeners     []*listener // The subscribers, by priority.
+	imgs          []*ebiten.Image
+
+	layers   []layerState
+	canvas   *ebiten.Image
+	layerImg *ebiten.Image
+	backdrop *ebiten.Image
 }
 
 // CreatePlayer returns a new animation player that plays animations from a given Aseprite file.
@@ -50,123 +77,350 @@ func (f *File) CreatePlayer() *Player {
 	}
 }
 
-func (f *File) CreatePlayerWithImage(img *ebiten.Image) *Player {
+// CreatePlayerWithImage returns a new animation player that draws the frames of the File from the given images, one for
+// each of its Pages, in order.
+func (f *File) CreatePlayerWithImage(imgs ...*ebiten.Image) *Player {
 	return &Player{
 		File:      f,
 		PlaySpeed: 1,
-		img:       img,
+		imgs:      imgs,
 	}
 }
 
-// Clone clones the Player.
+// Clone clones the Player, along with its callbacks. Subscribers aren't copied.
 func (p *Player) Clone() *Player {
-	newPlayer := p.File.CreatePlayer()
+	newPlayer := p.File.CreatePlayerWithImage(p.imgs...)
 	newPlayer.PlaySpeed = p.PlaySpeed
 	newPlayer.CurrentTag = p.CurrentTag
 	newPlayer.FrameIndex = p.FrameIndex
 	newPlayer.frameCounter = p.frameCounter
+	newPlayer.playDirection = p.playDirection
+	newPlayer.passes = p.passes
+	newPlayer.step = p.step
+	newPlayer.finished = p.finished
+	newPlayer.options = p.options
+	newPlayer.prevTag, newPlayer.prevOptions = p.prevTag, p.prevOptions
+	newPlayer.queue = append([]queueEntry(nil), p.queue...)
+	newPlayer.InterpolateSlices = p.InterpolateSlices
+	newPlayer.layers = append([]layerState(nil), p.layers...)
 
 	newPlayer.OnLoop = p.OnLoop
 	newPlayer.OnFrameChange = p.OnFrameChange
 	newPlayer.OnTagEnter = p.OnTagEnter
 	newPlayer.OnTagExit = p.OnTagExit
+	newPlayer.OnFinish = p.OnFinish
+	newPlayer.OnQueueNext = p.OnQueueNext
+	newPlayer.OnEvent = p.OnEvent
 
 	return newPlayer
 }
 
+// Draw draws the current frame onto screen. Trimmed and rotated frames are placed back where they belong within the
+// original frame, so they render just like untrimmed ones. Files exported with "Split Layers" are drawn compositing the
+// visible layers in order. During a crossfade, the outgoing animation is drawn first, fading out, and then the current
+// one fading in; OnDraw and the subscribers get called for both, with the outgoing Player first and the options already
+// scaled by their alpha.
 func (p *Player) Draw(screen *ebiten.Image) error {
+	if p.fade == nil {
+		return p.draw(screen, 1, p)
+	}
+
+	weight := p.fade.Weight()
+	if err := p.fade.From.draw(screen, 1-weight, p); err != nil {
+		return err
+	}
+
+	return p.draw(screen, weight, p)
+}
+
+// draw draws the current frame onto screen with the given alpha, emitting a DrawEvent to the subscribers of owner
+// before.
+func (p *Player) draw(screen *ebiten.Image, alpha float32, owner *Player) error {
+	if p.CurrentTag == nil {
+		return nil
+	}
+
 	opts := &ebiten.DrawImageOptions{}
-	sub := p.img.SubImage(image.Rect(p.CurrentFrameCoords())).(*ebiten.Image)
+	opts.ColorScale.ScaleAlpha(alpha)
+
+	var img *ebiten.Image
+	if p.File.HasSplitLayers() {
+		var err error
+		if img, err = p.drawLayers(); err != nil {
+			return err
+		}
+	} else {
+		frame := p.File.Frames[p.FrameIndex]
+		opts.GeoM = frameGeoM(frame)
+		img = p.frameImage(frame)
+	}
 
-	if p.OnDraw != nil {
-		if stop := p.OnDraw(p, screen, sub, opts); stop {
+	if img == nil {
+		return nil
+	}
+
+	if owner.OnDraw != nil || owner.hasListeners() {
+		e := &DrawEvent{Player: p, Screen: screen, Image: img, Options: opts}
+		if owner.emit(e); e.Abort {
 			return nil
 		}
 	}
 
-	screen.DrawImage(sub, opts)
+	screen.DrawImage(img, opts)
 	return nil
 }
 
+// frameImage returns the sub-image of the frame on its page, or nil if the Player doesn't have an image for the page.
+func (p *Player) frameImage(frame Frame) *ebiten.Image {
+	if frame.Page >= len(p.imgs) {
+		return nil
+	}
+
+	return p.imgs[frame.Page].SubImage(p.File.frameRect(frame)).(*ebiten.Image)
+}
+
 // Play sets the specified tag name up to be played back. A tagName of "" will play back the entire file.
-func (p *Player) Play(tagName string) error {
+// An optional PlayOptions sets how the tag is played; by default it loops, or plays as many times as its Repeat.
+// Playing the tag that is already playing only changes its options, unless it finished, in which case it restarts.
+// Play clears the queue of animations and cuts any crossfade short.
+func (p *Player) Play(tagName string, opts ...PlayOptions) error {
 	t, ok := p.File.Tags[tagName]
 	if !ok {
 		return ErrNoTagByName
 
 	}
 
-	if p.CurrentTag == t {
-		return nil
+	var o PlayOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	p.ClearQueue()
+	p.fade = nil
+	p.play(t, o)
+	return nil
+}
+
+func (p *Player) play(t *Tag, opts PlayOptions) {
+	if p.CurrentTag == t && !p.finished {
+		p.options = opts
+		return
 	}
 
 	if p.CurrentTag == nil {
 		p.PrevFrameIndex = -1
 	} else {
 		p.PrevFrameIndex = p.FrameIndex
+		p.prevTag, p.prevOptions = p.CurrentTag, p.options
 	}
 
 	p.CurrentTag = t
+	p.options = opts
 	p.frameCounter = 0
+	p.passes = 0
+	p.step = 0
+	p.finished = false
 
-	if t.Direction == PlayBackward {
+	p.FrameIndex = p.firstFrame()
+	if t.Direction.reversed() {
 		p.playDirection = -1
-		p.FrameIndex = p.CurrentTag.End
 	} else {
 		p.playDirection = 1
-		p.FrameIndex = p.CurrentTag.Start
 	}
 
 	p.pollTagChanges()
-	return nil
+	p.fireEvents()
 }
 
 // Update updates the currently playing animation. dt is the delta value between the previous frame and the current frame.
+// A negative speed plays the animation backwards, going through the frames in the opposite order.
 func (p *Player) Update(dt float32) {
+	p.updateCrossfade(dt)
+
 	if p.CurrentTag == nil {
 		return
 	}
 
-	t := p.CurrentTag
-	p.frameCounter += dt * p.PlaySpeed
-	frameDur := p.File.Frames[p.FrameIndex].Duration
+	delta := dt * p.Speed()
+	if p.finished {
+		// A finished tag can only be played backwards, from where it stopped.
+		if delta >= 0 || p.step == 0 {
+			return
+		}
+
+		p.finished = false
+	}
+
+	p.frameCounter += delta
 	p.prevUVX, p.prevUVY = p.CurrentUVCoords()
 
-	for p.frameCounter >= frameDur {
-		p.frameCounter -= frameDur
+	// Frames with no duration are skipped. If every frame of the tag has none, which going through a whole ping-pong
+	// loop without finding one that has tells, skipping them would never end.
+	maxSkips := 2 * (p.CurrentTag.End - p.CurrentTag.Start + 1)
+	skips := 0
+
+	for p.frameCounter >= p.File.Frames[p.FrameIndex].Duration {
+		if skips = p.countSkip(skips); skips > maxSkips {
+			p.endWithoutDuration(true)
+			return
+		}
+
+		p.frameCounter -= p.File.Frames[p.FrameIndex].Duration
 		p.PrevFrameIndex = p.FrameIndex
-		p.FrameIndex += p.playDirection
 
-		if t.Direction == PlayPingPong {
-			if p.FrameIndex > t.End {
-				p.FrameIndex = t.End - 1
-				p.playDirection *= -1
-			} else if p.FrameIndex < t.Start {
-				p.FrameIndex = t.Start + 1
-				p.playDirection *= -1
-				if p.OnLoop != nil {
-					p.OnLoop(p)
-				}
-			}
-
-		} else if p.playDirection > 0 && p.FrameIndex > t.End {
-			p.FrameIndex -= t.End - t.Start + 1
-			if p.OnLoop != nil {
-				p.OnLoop(p)
-			}
-		} else if p.playDirection < 0 && p.FrameIndex < t.Start {
-			p.FrameIndex += t.End - t.Start + 1
-			if p.OnLoop != nil {
-				p.OnLoop(p)
-			}
+		looped, done := p.nextFrame()
+		if looped {
+			p.emit(&LoopEvent{Player: p})
+		}
+
+		if done {
+			p.finish()
+			return
 		}
 
-		if p.FrameIndex != p.PrevFrameIndex && p.OnFrameChange != nil {
-			p.OnFrameChange(p, p.FrameIndex)
+		if p.FrameIndex != p.PrevFrameIndex {
+			p.emit(&FrameChangeEvent{Player: p, Frame: p.FrameIndex, PrevFrame: p.PrevFrameIndex})
 		}
 
 		p.pollTagChanges()
+		p.fireEvents()
+	}
+
+	skips = 0
+	for p.frameCounter < 0 {
+		if skips = p.countSkip(skips); skips > maxSkips {
+			p.endWithoutDuration(false)
+			return
+		}
+
+		p.PrevFrameIndex = p.FrameIndex
+
+		looped, done := p.prevFrame()
+		if looped {
+			p.emit(&LoopEvent{Player: p})
+		}
+
+		if done {
+			p.frameCounter = 0
+			p.finish()
+			return
+		}
+
+		p.frameCounter += p.File.Frames[p.FrameIndex].Duration
+
+		if p.FrameIndex != p.PrevFrameIndex {
+			p.emit(&FrameChangeEvent{Player: p, Frame: p.FrameIndex, PrevFrame: p.PrevFrameIndex})
+		}
+
+		p.pollTagChanges()
+		p.fireEvents()
+	}
+}
+
+// Reverse reverses the playback of the current animation, negating PlaySpeed.
+func (p *Player) Reverse() {
+	p.PlaySpeed = -p.PlaySpeed
+}
+
+// prevFrame moves to the previous frame of the playing tag, for negative play speeds, wrapping around at the start
+// of a loop. It returns whether the animation looped, and whether it's done instead of moving: because the tag doesn't
+// loop and it's back at its start, or because it loops and there are animations waiting in the queue.
+func (p *Player) prevFrame() (looped, done bool) {
+	tl := p.timeline()
+	if p.step > tl.length() {
+		p.step = tl.length()
+	}
+
+	if p.step == 0 {
+		if tl.limit > 0 {
+			return false, true
+		}
+
+		if len(p.queue) > 0 {
+			return true, true
+		}
+
+		p.step = tl.length()
+		looped = true
+	}
+
+	p.step--
+	p.FrameIndex, p.playDirection, p.passes = tl.at(p.step)
+	return looped, false
+}
+
+// nextFrame moves to the next frame of the playing tag, wrapping around or bouncing at its ends. It returns whether
+// the animation looped, and whether it's done instead of moving: because the tag played all its repeats, or because
+// it loops and there are animations waiting in the queue.
+func (p *Player) nextFrame() (looped, done bool) {
+	t := p.CurrentTag
+	next := p.FrameIndex + p.playDirection
+	if next >= t.Start && next <= t.End {
+		p.FrameIndex = next
+		p.step++
+		return false, false
+	}
+
+	// The end of a pass through the tag.
+	p.passes++
+	limit := p.passLimit()
+	if limit > 0 && p.passes >= limit {
+		return false, true
+	}
+
+	if limit == 0 && len(p.queue) > 0 && p.passes%p.passesPerLoop() == 0 {
+		return true, true
 	}
+
+	p.step++
+	if limit == 0 {
+		p.step %= p.timeline().length()
+	}
+
+	if t.Direction.pingPong() && t.Start < t.End {
+		p.playDirection *= -1
+		p.FrameIndex += p.playDirection
+
+		// For a ping-pong, a loop is a complete forward + back cycle.
+		return p.passes%2 == 0, false
+	}
+
+	if p.playDirection > 0 {
+		p.FrameIndex = t.Start
+	} else {
+		p.FrameIndex = t.End
+	}
+
+	return true, false
+}
+
+// countSkip returns the number of frames with no duration the Player went through in a row, counting the current
+// one.
+func (p *Player) countSkip(skips int) int {
+	if p.File.Frames[p.FrameIndex].Duration > 0 {
+		return 0
+	}
+
+	return skips + 1
+}
+
+// endWithoutDuration stops going through the frames of a tag that has no duration at all. A tag that finishes goes
+// to its end, or its start if it's played backwards, and finishes right away; one that loops stays where it is.
+func (p *Player) endWithoutDuration(forward bool) {
+	p.frameCounter = 0
+
+	tl := p.timeline()
+	if tl.limit == 0 {
+		return
+	}
+
+	if forward {
+		p.seekStep(tl, tl.length()-1)
+	} else {
+		p.seekStep(tl, 0)
+	}
+
+	p.finish()
 }
 
 // TouchingTags returns the tags currently being touched by the Player (tag).
@@ -194,22 +448,21 @@ func (p *Player) TouchingTagByName(tagName string) bool {
 
 // pollTagChanges polls the File for tag changes (entering or exiting Tags).
 func (p *Player) pollTagChanges() {
-	if p.OnTagExit != nil {
-		for _, tag := range p.File.Tags {
-			if (p.PrevFrameIndex >= tag.Start && p.PrevFrameIndex <= tag.End) && (p.FrameIndex < tag.Start || p.FrameIndex > tag.End) {
-				p.OnTagExit(p, tag)
-			}
-		}
+	if !p.hasListeners() && p.OnTagExit == nil && p.OnTagEnter == nil {
+		return
 	}
 
-	if p.OnTagEnter != nil {
-		for _, tag := range p.File.Tags {
-			if (p.PrevFrameIndex < tag.Start || p.PrevFrameIndex > tag.End) && (p.FrameIndex >= tag.Start && p.FrameIndex <= tag.End) {
-				p.OnTagEnter(p, tag)
-			}
+	for _, tag := range p.File.Tags {
+		if (p.PrevFrameIndex >= tag.Start && p.PrevFrameIndex <= tag.End) && (p.FrameIndex < tag.Start || p.FrameIndex > tag.End) {
+			p.emit(&TagExitEvent{Player: p, Tag: tag})
 		}
 	}
 
+	for _, tag := range p.File.Tags {
+		if (p.PrevFrameIndex < tag.Start || p.PrevFrameIndex > tag.End) && (p.FrameIndex >= tag.Start && p.FrameIndex <= tag.End) {
+			p.emit(&TagEnterEvent{Player: p, Tag: tag})
+		}
+	}
 }
 
 // CurrentFrame returns the current frame for the currently playing Tag in the File and a boolean indicating if the Player is playing a Tag or not.
@@ -229,8 +482,35 @@ func (p *Player) CurrentFrameCoords() (int, int, int, int) {
 		return -1, -1, -1, -1
 	}
 
-	return frame.X, frame.Y, frame.X + int(p.File.FrameWidth), frame.Y + int(p.File.FrameHeight)
+	r := p.File.frameRect(frame)
+	return r.Min.X, r.Min.Y, r.Max.X, r.Max.Y
+}
+
+// frameGeoM returns the transformation that moves the frame's sub-image to its place in the original frame, undoing
+// the rotation and trimming done when the spritesheet was packed.
+func frameGeoM(frame Frame) ebiten.GeoM {
+	g := ebiten.GeoM{}
+	switch {
+	case frame.Rotated && frame.RotatedCCW:
+		g.Rotate(math.Pi / 2)
+		g.Translate(float64(frame.W), 0)
+	case frame.Rotated:
+		g.Rotate(-math.Pi / 2)
+		g.Translate(0, float64(frame.H))
+	}
 
+	g.Translate(float64(frame.OffsetX), float64(frame.OffsetY))
+	return g
+}
+
+// CurrentFrameSize returns the width and height of the current frame, as it was before being trimmed. If
+// File.CurrentFrame() is nil, it will instead return (-1, -1).
+func (p *Player) CurrentFrameSize() (int, int) {
+	if p.CurrentTag == nil {
+		return -1, -1
+	}
+
+	return p.File.FrameSize(p.FrameIndex)
 }
 
 // CurrentUVCoords returns the top-left corner of the current frame, of format (x, y). If File.CurrentFrame() is nil, it will instead
@@ -241,10 +521,25 @@ func (p *Player) CurrentUVCoords() (float64, float64) {
 		return -1, -1
 	}
 
-	return float64(frame.X) / float64(p.File.Width), float64(frame.Y) / float64(p.File.Height)
+	w, h := p.File.PageSize(frame.Page)
+	return float64(frame.X) / float64(w), float64(frame.Y) / float64(h)
 
 }
 
+// CurrentUVRect returns the four corners of the current frame in UV coordinates, of format (u1, v1, u2, v2). If
+// File.CurrentFrame() is nil, it will instead return all -1's.
+func (p *Player) CurrentUVRect() (float64, float64, float64, float64) {
+	frame, ok := p.CurrentFrame()
+	if !ok {
+		return -1, -1, -1, -1
+	}
+
+	r := p.File.frameRect(frame)
+	pw, ph := p.File.PageSize(frame.Page)
+	w, h := float64(pw), float64(ph)
+	return float64(r.Min.X) / w, float64(r.Min.Y) / h, float64(r.Max.X) / w, float64(r.Max.Y) / h
+}
+
 // CurrentUVCoordsDelta returns the current UV Coords as a coordinate movement delta.
 // For example, if an animation were to return the X-axis UV coordinates of :
 // [ 0, 0, 0, 0, 0.5, 0.5, 0.5, 0.5 ],
@@ -268,6 +563,15 @@ func (p *Player) SetFrameIndex(frameIndex int) {
 		p.FrameIndex = p.CurrentTag.End
 	}
 	p.frameCounter = 0
+
+	// Continue from where the frame first shows up in the timeline.
+	tl := p.timeline()
+	for step := 0; step < tl.length(); step++ {
+		if frame, _, _ := tl.at(step); frame == p.FrameIndex {
+			p.seekStep(tl, step)
+			break
+		}
+	}
 }
 
 // FrameIndexInAnimation returns the currently visible frame index, using the playing animation as the range.
@@ -0,0 +1,262 @@
+package sprite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlayerCurrentFrameCoordsTrimmedRotated(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteTrimmed))
+	require.NoError(t, err)
+
+	p := f.CreatePlayer()
+	require.NoError(t, p.Play(""))
+
+	x1, y1, x2, y2 := p.CurrentFrameCoords()
+	assert.Equal(t, []int{2, 4, 12, 16}, []int{x1, y1, x2, y2})
+
+	p.SetFrameIndex(1)
+	x1, y1, x2, y2 = p.CurrentFrameCoords()
+	assert.Equal(t, []int{14, 4, 28, 10}, []int{x1, y1, x2, y2})
+}
+
+func TestFrameGeoM(t *testing.T) {
+	g := frameGeoM(Frame{W: 6, H: 14, Rotated: true, OffsetX: 5, OffsetY: 2})
+
+	// The top-left corner of a frame stored rotated clockwise is its bottom-left corner.
+	x, y := g.Apply(0, 0)
+	assert.InDelta(t, 5, x, 1e-9)
+	assert.InDelta(t, 16, y, 1e-9)
+
+	x, y = g.Apply(14, 6)
+	assert.InDelta(t, 11, x, 1e-9)
+	assert.InDelta(t, 2, y, 1e-9)
+
+	g = frameGeoM(Frame{W: 10, H: 12, OffsetX: 3, OffsetY: 1})
+	x, y = g.Apply(0, 0)
+	assert.InDelta(t, 3, x, 1e-9)
+	assert.InDelta(t, 1, y, 1e-9)
+}
+
+func TestPlayerLayers(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteSplitLayers))
+	require.NoError(t, err)
+
+	p := f.CreatePlayer()
+	assert.True(t, p.LayerVisible("Layer 10"))
+	assert.Equal(t, p.LayerOpacity("Layer 10"), uint8(128))
+
+	require.NoError(t, p.SetLayerVisible("Layer 10", false))
+	require.NoError(t, p.SetLayerOpacity("Layer 1", 64))
+	assert.False(t, p.LayerVisible("Layer 10"))
+	assert.Equal(t, p.LayerOpacity("Layer 1"), uint8(64))
+
+	assert.ErrorIs(t, p.SetLayerVisible("Layer 2", true), ErrNoLayerByName)
+	assert.False(t, p.LayerVisible("Layer 2"))
+
+	// Layer states are per Player.
+	clone := p.Clone()
+	require.NoError(t, clone.SetLayerVisible("Layer 10", true))
+	assert.False(t, p.LayerVisible("Layer 10"))
+	assert.True(t, f.CreatePlayer().LayerVisible("Layer 10"))
+}
+
+var asepriteDirections = `{ "frames": [
+	{ "filename": "hero 0.aseprite", "frame": { "x": 0, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 100 },
+	{ "filename": "hero 1.aseprite", "frame": { "x": 16, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 100 },
+	{ "filename": "hero 2.aseprite", "frame": { "x": 32, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 100 },
+	{ "filename": "hero 3.aseprite", "frame": { "x": 48, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 100 }
+ ],
+ "meta": {
+	"image": "hero.png", "size": { "w": 64, "h": 16 },
+	"frameTags": [
+		{ "name": "forward", "from": 0, "to": 2, "direction": "forward", "repeat": "2" },
+		{ "name": "reverse", "from": 1, "to": 3, "direction": "reverse" },
+		{ "name": "pingpong", "from": 0, "to": 2, "direction": "pingpong" },
+		{ "name": "pingpong_reverse", "from": 0, "to": 2, "direction": "pingpong_reverse", "repeat": "3" }
+	]
+ }
+}`
+
+// playFrames plays the tag and returns the frames shown on each of the given number of updates of a frame's duration,
+// starting with the first one, and the number of loops and finishes.
+func playFrames(t *testing.T, p *Player, tagName string, updates int, opts ...PlayOptions) ([]int, int, int) {
+	loops, finishes := 0, 0
+	p.OnLoop = func(p *Player) { loops++ }
+	p.OnFinish = func(p *Player) { finishes++ }
+
+	require.NoError(t, p.Play(tagName, opts...))
+	frames := []int{p.FrameIndex}
+	for i := 0; i < updates; i++ {
+		p.Update(0.1)
+		frames = append(frames, p.FrameIndex)
+	}
+
+	return frames, loops, finishes
+}
+
+func TestPlayerDirections(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteDirections))
+	require.NoError(t, err)
+	assert.Equal(t, f.Tags["forward"].Repeat, 2)
+	assert.Equal(t, f.Tags["pingpong_reverse"].Direction, PlayPingPongReverse)
+
+	frames, loops, finishes := playFrames(t, f.CreatePlayer(), "forward", 7)
+	assert.Equal(t, []int{0, 1, 2, 0, 1, 2, 2, 2}, frames)
+	assert.Equal(t, 1, loops)
+	assert.Equal(t, 1, finishes)
+
+	frames, loops, finishes = playFrames(t, f.CreatePlayer(), "reverse", 6)
+	assert.Equal(t, []int{3, 2, 1, 3, 2, 1, 3}, frames)
+	assert.Equal(t, 2, loops)
+	assert.Equal(t, 0, finishes)
+
+	frames, loops, _ = playFrames(t, f.CreatePlayer(), "pingpong", 8)
+	assert.Equal(t, []int{0, 1, 2, 1, 0, 1, 2, 1, 0}, frames)
+	assert.Equal(t, 1, loops)
+
+	frames, loops, finishes = playFrames(t, f.CreatePlayer(), "pingpong_reverse", 8)
+	assert.Equal(t, []int{2, 1, 0, 1, 2, 1, 0, 0, 0}, frames)
+	assert.Equal(t, 1, loops)
+	assert.Equal(t, 1, finishes)
+}
+
+func TestPlayerReplayFinished(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteDirections))
+	require.NoError(t, err)
+
+	p := f.CreatePlayer()
+	playFrames(t, p, "forward", 6)
+	assert.Equal(t, p.FrameIndex, 2)
+
+	// Playing a finished tag again restarts it.
+	require.NoError(t, p.Play("forward"))
+	assert.Equal(t, p.FrameIndex, 0)
+}
+
+func TestPlayerPlayModes(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteDirections))
+	require.NoError(t, err)
+
+	p := f.CreatePlayer()
+	frames, _, finishes := playFrames(t, p, "forward", 7, PlayOptions{Mode: PlayModeLoop})
+	assert.Equal(t, []int{0, 1, 2, 0, 1, 2, 0, 1}, frames)
+	assert.Equal(t, 0, finishes)
+	assert.False(t, p.IsFinished())
+
+	p = f.CreatePlayer()
+	frames, _, finishes = playFrames(t, p, "reverse", 4, PlayOptions{Mode: PlayModeOnce})
+	assert.Equal(t, []int{3, 2, 1, 3, 3}, frames)
+	assert.Equal(t, 1, finishes)
+	assert.True(t, p.IsFinished())
+
+	p = f.CreatePlayer()
+	frames, _, finishes = playFrames(t, p, "pingpong", 6, PlayOptions{Mode: PlayModeOnceHold})
+	assert.Equal(t, []int{0, 1, 2, 1, 0, 0, 0}, frames)
+	assert.Equal(t, 1, finishes)
+
+	p = f.CreatePlayer()
+	frames, _, finishes = playFrames(t, p, "forward", 10, PlayOptions{Mode: PlayModeRepeat, Repeat: 3})
+	assert.Equal(t, []int{0, 1, 2, 0, 1, 2, 0, 1, 2, 2, 2}, frames)
+	assert.Equal(t, 1, finishes)
+}
+
+func TestPlayerPlayModeOnceReturn(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteDirections))
+	require.NoError(t, err)
+
+	p := f.CreatePlayer()
+	require.NoError(t, p.Play("reverse"))
+	p.Update(0.1)
+	require.NoError(t, p.Play("forward", PlayOptions{Mode: PlayModeOnceReturn}))
+
+	frames := []int{p.FrameIndex}
+	for i := 0; i < 4; i++ {
+		p.Update(0.1)
+		frames = append(frames, p.FrameIndex)
+	}
+
+	assert.Equal(t, []int{0, 1, 2, 3, 2}, frames)
+	assert.Equal(t, p.CurrentTag.Name, "reverse")
+	assert.False(t, p.IsFinished())
+}
+
+func TestPlayerNegativeSpeed(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteDirections))
+	require.NoError(t, err)
+
+	p := f.CreatePlayer()
+	p.PlaySpeed = -1
+	frames, loops, _ := playFrames(t, p, "reverse", 4)
+	assert.Equal(t, []int{3, 1, 2, 3, 1}, frames)
+	assert.Equal(t, 2, loops)
+
+	frames, loops, _ = playFrames(t, p, "pingpong", 5)
+	assert.Equal(t, []int{0, 1, 2, 1, 0, 1}, frames)
+	assert.Equal(t, 2, loops)
+}
+
+func TestPlayerReverse(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteDirections))
+	require.NoError(t, err)
+
+	p := f.CreatePlayer()
+	_, _, finishes := playFrames(t, p, "forward", 7)
+	assert.Equal(t, 1, finishes)
+	assert.True(t, p.IsFinished())
+	p.OnFinish = func(p *Player) { finishes++ }
+
+	// A finished tag rewinds from where it stopped, and finishes again at its start.
+	p.Reverse()
+	assert.Equal(t, p.PlaySpeed, float32(-1))
+
+	frames := []int{}
+	for i := 0; i < 6; i++ {
+		p.Update(0.1)
+		frames = append(frames, p.FrameIndex)
+	}
+
+	assert.Equal(t, []int{1, 0, 2, 1, 0, 0}, frames)
+	assert.True(t, p.IsFinished())
+	assert.Equal(t, 2, finishes)
+
+	p.Reverse()
+	assert.Equal(t, p.PlaySpeed, float32(1))
+}
+
+func TestPlayerZeroDuration(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteDirections))
+	require.NoError(t, err)
+
+	// Frames with no duration are skipped.
+	f.Frames[1].Duration = 0
+	frames, _, _ := playFrames(t, f.CreatePlayer(), "forward", 2)
+	assert.Equal(t, []int{0, 2, 0}, frames)
+
+	for i := range f.Frames {
+		f.Frames[i].Duration = 0
+	}
+
+	// Tags with no duration at all finish right away, either way.
+	frames, loops, finishes := playFrames(t, f.CreatePlayer(), "forward", 1)
+	assert.Equal(t, []int{0, 2}, frames)
+	assert.Equal(t, 1, loops)
+	assert.Equal(t, 1, finishes)
+
+	p := f.CreatePlayer()
+	frames, _, finishes = playFrames(t, p, "forward", 1, PlayOptions{Mode: PlayModeRepeat, Repeat: 5})
+	assert.Equal(t, []int{0, 2}, frames)
+	p.OnFinish = func(p *Player) { finishes++ }
+	p.PlaySpeed = -1
+	p.Update(0.1)
+	assert.Equal(t, p.FrameIndex, 0)
+	assert.True(t, p.IsFinished())
+	assert.Equal(t, finishes, 2)
+
+	// Looping ones stay where they are.
+	frames, _, finishes = playFrames(t, f.CreatePlayer(), "reverse", 2)
+	assert.Equal(t, []int{3, 3, 3}, frames)
+	assert.Equal(t, 0, finishes)
+}
@@ -0,0 +1,138 @@
+package sprite
+
+// PlayMode is how a Player plays a Tag, and what it does when the Tag finishes.
+type PlayMode int
+
+const (
+	// PlayModeDefault plays the Tag as many times as its Repeat and holds its last frame, or loops if it has none.
+	PlayModeDefault PlayMode = iota
+	// PlayModeLoop loops the Tag forever, ignoring its Repeat.
+	PlayModeLoop
+	// PlayModeOnce plays the Tag once and stops on its first frame.
+	PlayModeOnce
+	// PlayModeOnceHold plays the Tag once and holds its last frame.
+	PlayModeOnceHold
+	// PlayModeOnceReturn plays the Tag once and then goes back to the Tag that was playing before it.
+	PlayModeOnceReturn
+	// PlayModeRepeat plays the Tag PlayOptions.Repeat times and holds its last frame.
+	PlayModeRepeat
+)
+
+// playModeNames are the names of the PlayModes, in order.
+var playModeNames = []string{"default", "loop", "once", "once_hold", "once_return", "repeat"}
+
+// ParsePlayMode returns the PlayMode for a name ("loop", "once_hold"...). Unknown names are PlayModeDefault.
+func ParsePlayMode(name string) PlayMode {
+	for i, n := range playModeNames {
+		if n == name {
+			return PlayMode(i)
+		}
+	}
+
+	return PlayModeDefault
+}
+
+// String returns the name of the PlayMode.
+func (m PlayMode) String() string {
+	if m < 0 || int(m) >= len(playModeNames) {
+		return playModeNames[PlayModeDefault]
+	}
+
+	return playModeNames[m]
+}
+
+// PlayOptions are the options to play a Tag with.
+type PlayOptions struct {
+	Mode   PlayMode // How to play the Tag.
+	Repeat int      // The number of times to play the Tag with PlayModeRepeat; at least once.
+	Speed  float32  // The speed to play the Tag at, relative to the Player's PlaySpeed; 0 is the same as 1.
+}
+
+// Speed returns the speed the playing Tag advances at: the Player's PlaySpeed times the Speed it was played with.
+func (p *Player) Speed() float32 {
+	if p.options.Speed == 0 {
+		return p.PlaySpeed
+	}
+
+	return p.PlaySpeed * p.options.Speed
+}
+
+// IsFinished returns true if the playing Tag finished, which never happens while looping.
+func (p *Player) IsFinished() bool {
+	return p.finished
+}
+
+// passLimit returns the number of passes through the playing tag after which it finishes, or 0 if it loops forever.
+// Every play mode but the default one counts a complete forward + back cycle of a ping-pong as a single time.
+func (p *Player) passLimit() int {
+	passesPerLoop := p.passesPerLoop()
+
+	switch p.options.Mode {
+	case PlayModeLoop:
+		return 0
+	case PlayModeOnce, PlayModeOnceHold, PlayModeOnceReturn:
+		return passesPerLoop
+	case PlayModeRepeat:
+		if p.options.Repeat < 1 {
+			return passesPerLoop
+		}
+
+		return p.options.Repeat * passesPerLoop
+	}
+
+	return p.CurrentTag.Repeat
+}
+
+// passesPerLoop returns the number of passes through the playing tag in a loop: two for a ping-pong, one otherwise.
+func (p *Player) passesPerLoop() int {
+	if t := p.CurrentTag; t.Direction.pingPong() && t.Start < t.End {
+		return 2
+	}
+
+	return 1
+}
+
+// firstFrame returns the frame the playing tag starts on.
+func (p *Player) firstFrame() int {
+	if p.CurrentTag.Direction.reversed() {
+		return p.CurrentTag.End
+	}
+
+	return p.CurrentTag.Start
+}
+
+// finish stops the playing tag, which played all its times or has to give way to the queue, according to its play
+// mode, and plays what comes next.
+func (p *Player) finish() {
+	t := p.CurrentTag
+
+	if p.passLimit() > 0 {
+		p.finished = true
+		p.frameCounter = 0
+
+		if p.options.Mode == PlayModeOnce {
+			p.PrevFrameIndex = p.FrameIndex
+			p.FrameIndex = p.firstFrame()
+			p.step = 0
+
+			if p.FrameIndex != p.PrevFrameIndex {
+				p.emit(&FrameChangeEvent{Player: p, Frame: p.FrameIndex, PrevFrame: p.PrevFrameIndex})
+			}
+		}
+
+		p.emit(&FinishEvent{Player: p, Tag: t})
+
+		// OnFinish may have played another tag already.
+		if p.CurrentTag != t || !p.finished {
+			return
+		}
+	}
+
+	if p.Skip() {
+		return
+	}
+
+	if p.options.Mode == PlayModeOnceReturn && p.prevTag != nil {
+		p.play(p.prevTag, p.prevOptions)
+	}
+}
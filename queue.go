@@ -0,0 +1,60 @@
+package sprite
+
+// queueEntry is an animation waiting in the queue of a Player.
+type queueEntry struct {
+	tag     *Tag
+	options PlayOptions
+}
+
+// Enqueue adds the specified tag name to the queue of animations, to be played with the optional PlayOptions after
+// the ones before it. Each animation in the queue starts when the previous one finishes or, if it loops, at the end of
+// its current loop. If nothing is playing, or the playing tag already finished, the tag starts playing right away.
+func (p *Player) Enqueue(tagName string, opts ...PlayOptions) error {
+	t, ok := p.File.Tags[tagName]
+	if !ok {
+		return ErrNoTagByName
+	}
+
+	entry := queueEntry{tag: t}
+	if len(opts) > 0 {
+		entry.options = opts[0]
+	}
+
+	p.queue = append(p.queue, entry)
+	if p.CurrentTag == nil || p.finished {
+		p.Skip()
+	}
+
+	return nil
+}
+
+// ClearQueue removes all the animations waiting in the queue. The playing one keeps playing.
+func (p *Player) ClearQueue() {
+	p.queue = nil
+}
+
+// QueueLen returns the number of animations waiting in the queue.
+func (p *Player) QueueLen() int {
+	return len(p.queue)
+}
+
+// Skip skips the playing animation and plays the next one in the queue right away, calling OnQueueNext. It returns
+// false, and keeps playing, if the queue is empty.
+func (p *Player) Skip() bool {
+	if len(p.queue) == 0 {
+		return false
+	}
+
+	entry := p.queue[0]
+	p.queue = p.queue[1:]
+
+	// Queuing the tag that is playing restarts it.
+	if p.CurrentTag == entry.tag {
+		p.finished = true
+	}
+
+	p.play(entry.tag, entry.options)
+	p.emit(&QueueNextEvent{Player: p, Tag: entry.tag})
+
+	return true
+}
@@ -0,0 +1,91 @@
+package sprite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlayerQueue(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteDirections))
+	require.NoError(t, err)
+
+	p := f.CreatePlayer()
+
+	var started []string
+	p.OnQueueNext = func(p *Player, t *Tag) { started = append(started, t.Name) }
+
+	require.NoError(t, p.Play("reverse"))
+	require.NoError(t, p.Enqueue("forward", PlayOptions{Mode: PlayModeOnceHold}))
+	require.NoError(t, p.Enqueue("pingpong", PlayOptions{Speed: 2}))
+	assert.Equal(t, p.QueueLen(), 2)
+	assert.ErrorIs(t, p.Enqueue("missing"), ErrNoTagByName)
+
+	frames := []int{p.FrameIndex}
+	for i := 0; i < 8; i++ {
+		p.Update(0.1)
+		frames = append(frames, p.FrameIndex)
+	}
+
+	// The looping tag ends its loop, the next one plays once, and the last one loops at double speed.
+	assert.Equal(t, []int{3, 2, 1, 0, 1, 2, 0, 2, 0}, frames)
+	assert.Equal(t, []string{"forward", "pingpong"}, started)
+	assert.Equal(t, p.CurrentTag.Name, "pingpong")
+	assert.Equal(t, p.Speed(), float32(2))
+	assert.Equal(t, p.PlaySpeed, float32(1))
+	assert.Equal(t, p.QueueLen(), 0)
+}
+
+func TestPlayerQueueSpeed(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteDirections))
+	require.NoError(t, err)
+
+	p := f.CreatePlayer()
+	p.PlaySpeed = 0.5
+
+	require.NoError(t, p.Play("forward", PlayOptions{Mode: PlayModeOnce}))
+	require.NoError(t, p.Enqueue("reverse", PlayOptions{Mode: PlayModeOnce, Speed: 4}))
+	require.NoError(t, p.Enqueue("forward"))
+
+	// The speed of an entry only applies while it plays, on top of the Player's.
+	for i := 0; i < 3; i++ {
+		p.Update(0.2)
+	}
+	assert.Equal(t, p.CurrentTag.Name, "reverse")
+	assert.Equal(t, p.Speed(), float32(2))
+
+	for i := 0; i < 3; i++ {
+		p.Update(0.05)
+	}
+	assert.Equal(t, p.CurrentTag.Name, "forward")
+	assert.Equal(t, p.Speed(), float32(0.5))
+	assert.Equal(t, p.PlaySpeed, float32(0.5))
+}
+
+func TestPlayerQueueSkipAndClear(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteDirections))
+	require.NoError(t, err)
+
+	p := f.CreatePlayer()
+
+	// With nothing playing the tag starts right away.
+	require.NoError(t, p.Enqueue("forward"))
+	assert.Equal(t, p.CurrentTag.Name, "forward")
+	assert.Equal(t, p.QueueLen(), 0)
+
+	require.NoError(t, p.Enqueue("reverse"))
+	require.NoError(t, p.Enqueue("forward"))
+	assert.True(t, p.Skip())
+	assert.Equal(t, p.CurrentTag.Name, "reverse")
+	assert.Equal(t, p.FrameIndex, 3)
+
+	p.ClearQueue()
+	assert.False(t, p.Skip())
+	assert.Equal(t, p.CurrentTag.Name, "reverse")
+
+	// Playing a tag directly drops the queue.
+	require.NoError(t, p.Enqueue("pingpong"))
+	require.NoError(t, p.Play("forward"))
+	assert.Equal(t, p.QueueLen(), 0)
+}
@@ -0,0 +1,204 @@
+package sprite
+
+import (
+	"errors"
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var (
+	ErrNoSliceByName = errors.New("no slices by name")
+)
+
+// KeyAt returns the key of the Slice on the given frame. As in Aseprite, a key holds from its frame until the next
+// key, so it's the last key that starts on or before the frame. It returns false if the Slice has no key yet on that
+// frame.
+func (s Slice) KeyAt(frame int) (SliceKey, bool) {
+	found := false
+	var key SliceKey
+	for _, k := range s.Keys {
+		if int(k.Frame) <= frame && (!found || k.Frame >= key.Frame) {
+			key, found = k, true
+		}
+	}
+
+	return key, found
+}
+
+// InterpolatedKeyAt returns the key of the Slice at a fractional frame position (2.5 is halfway through the frame 2),
+// with its bounds linearly interpolated between the key holding on that frame and the next one. Other fields are the
+// ones of the holding key. It returns false if the Slice has no key yet on that frame.
+func (s Slice) InterpolatedKeyAt(frame float64) (SliceKey, bool) {
+	key, ok := s.KeyAt(int(math.Floor(frame)))
+	if !ok {
+		return key, false
+	}
+
+	found := false
+	var next SliceKey
+	for _, k := range s.Keys {
+		if k.Frame > key.Frame && (!found || k.Frame < next.Frame) {
+			next, found = k, true
+		}
+	}
+
+	if !found {
+		return key, true
+	}
+
+	t := (frame - float64(key.Frame)) / float64(next.Frame-key.Frame)
+	lerp := func(a, b int) int {
+		return int(math.Round(float64(a) + float64(b-a)*t))
+	}
+
+	key.X, key.Y = lerp(key.X, next.X), lerp(key.Y, next.Y)
+	key.W, key.H = lerp(key.W, next.W), lerp(key.H, next.H)
+
+	return key, true
+}
+
+// CurrentSlice returns the key of the Slice with the given name on the current frame, or false if there's no such
+// Slice or it has no key yet on the current frame. If InterpolateSlices is set, the bounds are interpolated towards
+// the next key following the time elapsed on the current frame.
+func (p *Player) CurrentSlice(sliceName string) (SliceKey, bool) {
+	slice, ok := p.File.SliceByName(sliceName)
+	if !ok {
+		return SliceKey{}, false
+	}
+
+	return p.sliceKey(slice)
+}
+
+// sliceKey returns the key of the Slice on the current frame, interpolated if InterpolateSlices is set.
+func (p *Player) sliceKey(slice Slice) (SliceKey, bool) {
+	if !p.InterpolateSlices || p.CurrentTag == nil {
+		return slice.KeyAt(p.FrameIndex)
+	}
+
+	progress := 0.0
+	if duration := p.File.Frames[p.FrameIndex].Duration; duration > 0 {
+		progress = float64(p.frameCounter / duration)
+	}
+
+	// Interpolate towards the frame that comes next, without going past the ends of the playing tag.
+	frame := float64(p.FrameIndex) + progress*float64(p.playDirection)
+	frame = math.Max(float64(p.CurrentTag.Start), math.Min(float64(p.CurrentTag.End), frame))
+
+	return slice.InterpolatedKeyAt(frame)
+}
+
+// nineSlicePatches returns the source and destination rectangles of the nine patches needed to draw the key stretched
+// to w x h pixels. The source rectangles are relative to the sprite, and the destination ones to the top-left corner
+// of the drawn slice. Corners keep their size, unless the slice is drawn smaller than them; edges are stretched in one
+// direction and the center in both. Keys that aren't 9-slices have a single patch, stretched to the whole size.
+func nineSlicePatches(key SliceKey, w, h int) (src, dst []image.Rectangle) {
+	if !key.HasCenter {
+		return []image.Rectangle{image.Rect(key.X, key.Y, key.X+key.W, key.Y+key.H)}, []image.Rectangle{image.Rect(0, 0, w, h)}
+	}
+
+	srcXs := [4]int{key.X, key.X + key.CenterX, key.X + key.CenterX + key.CenterW, key.X + key.W}
+	srcYs := [4]int{key.Y, key.Y + key.CenterY, key.Y + key.CenterY + key.CenterH, key.Y + key.H}
+	dstXs := nineSliceStops(key.CenterX, key.W-key.CenterX-key.CenterW, w)
+	dstYs := nineSliceStops(key.CenterY, key.H-key.CenterY-key.CenterH, h)
+
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			s := image.Rect(srcXs[col], srcYs[row], srcXs[col+1], srcYs[row+1])
+			d := image.Rect(dstXs[col], dstYs[row], dstXs[col+1], dstYs[row+1])
+			if s.Empty() || d.Empty() {
+				continue
+			}
+
+			src = append(src, s)
+			dst = append(dst, d)
+		}
+	}
+
+	return src, dst
+}
+
+// nineSliceStops returns the positions where the patches of a 9-slice start and end along an axis of the given size,
+// with the first and last patches as big as before and after. When the size is smaller than both they're shrunk
+// proportionally.
+func nineSliceStops(before, after, size int) [4]int {
+	if before+after > size {
+		before = size * before / (before + after)
+		after = size - before
+	}
+
+	return [4]int{0, before, size - after, size}
+}
+
+// DrawNineSlice draws the key of a Slice stretched to w x h pixels, keeping the corners of a 9-slice unscaled. src is
+// the image of the sprite the Slice belongs to, with its top-left corner at src.Bounds().Min. The given options are
+// applied after placing the patches, so opts.GeoM positions the top-left corner of the slice.
+func DrawNineSlice(dst, src *ebiten.Image, key SliceKey, w, h int, opts *ebiten.DrawImageOptions) {
+	if opts == nil {
+		opts = &ebiten.DrawImageOptions{}
+	}
+
+	origin := src.Bounds().Min
+	srcRects, dstRects := nineSlicePatches(key, w, h)
+	for i, s := range srcRects {
+		d := dstRects[i]
+
+		patchOpts := &ebiten.DrawImageOptions{
+			ColorScale: opts.ColorScale,
+			Blend:      opts.Blend,
+			Filter:     opts.Filter,
+		}
+		patchOpts.GeoM.Scale(float64(d.Dx())/float64(s.Dx()), float64(d.Dy())/float64(s.Dy()))
+		patchOpts.GeoM.Translate(float64(d.Min.X), float64(d.Min.Y))
+		patchOpts.GeoM.Concat(opts.GeoM)
+
+		dst.DrawImage(src.SubImage(s.Add(origin)).(*ebiten.Image), patchOpts)
+	}
+}
+
+// DrawNineSlice draws the Slice with the given name as it is on the current frame, stretched to w x h pixels and
+// keeping the corners of a 9-slice unscaled. See DrawNineSlice.
+func (p *Player) DrawNineSlice(screen *ebiten.Image, sliceName string, w, h int, opts *ebiten.DrawImageOptions) error {
+	slice, ok := p.File.SliceByName(sliceName)
+	if !ok {
+		return ErrNoSliceByName
+	}
+
+	key, ok := slice.KeyAt(p.FrameIndex)
+	if !ok || p.CurrentTag == nil {
+		return nil
+	}
+
+	img, err := p.spriteImage()
+	if err != nil || img == nil {
+		return err
+	}
+
+	DrawNineSlice(screen, img, key, w, h, opts)
+	return nil
+}
+
+// spriteImage returns the current frame as it is in the sprite, untrimmed and unrotated, which is the space Slices are
+// defined in. The top-left corner of the sprite is at the image's Bounds().Min.
+func (p *Player) spriteImage() (*ebiten.Image, error) {
+	if p.File.HasSplitLayers() {
+		return p.drawLayers()
+	}
+
+	frame := p.File.Frames[p.FrameIndex]
+	img := p.frameImage(frame)
+	if img == nil || !frame.Trimmed && !frame.Rotated && !frame.RotatedCCW {
+		return img, nil
+	}
+
+	w, h := p.File.FrameSize(p.FrameIndex)
+	p.resizeCanvas(w, h)
+	p.canvas.Clear()
+
+	opts := &ebiten.DrawImageOptions{}
+	opts.GeoM = frameGeoM(frame)
+	p.canvas.DrawImage(img, opts)
+
+	return p.canvas, nil
+}
@@ -0,0 +1,112 @@
+package sprite
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var asepriteSlices = `{ "frames": {
+	"panel 0.aseprite": { "frame": { "x": 0, "y": 0, "w": 32, "h": 32 }, "sourceSize": { "w": 32, "h": 32 }, "duration": 100 },
+	"panel 1.aseprite": { "frame": { "x": 32, "y": 0, "w": 32, "h": 32 }, "sourceSize": { "w": 32, "h": 32 }, "duration": 100 }
+ },
+ "meta": {
+	"image": "panel.png", "size": { "w": 64, "h": 32 },
+	"slices": [
+		{ "name": "panel", "color": "#0000ffff", "keys": [
+			{ "frame": 0, "bounds": { "x": 0, "y": 0, "w": 24, "h": 16 }, "center": { "x": 4, "y": 3, "w": 16, "h": 10 }, "pivot": { "x": 12, "y": 16 } }
+		] },
+		{ "name": "hit", "color": "#ff0000ff", "keys": [
+			{ "frame": 0, "bounds": { "x": 1, "y": 2, "w": 3, "h": 4 } },
+			{ "frame": 1, "bounds": { "x": 5, "y": 6, "w": 7, "h": 8 } }
+		] }
+	]
+ }
+}`
+
+func TestReadAsepriteSliceCenterAndPivot(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteSlices))
+	require.NoError(t, err)
+	require.Len(t, f.Slices, 2)
+
+	key := f.Slices[0].Keys[0]
+	assert.True(t, key.HasCenter)
+	assert.Equal(t, []int{4, 3, 16, 10}, []int{key.CenterX, key.CenterY, key.CenterW, key.CenterH})
+	assert.True(t, key.HasPivot)
+	assert.Equal(t, []int{12, 16}, []int{key.PivotX, key.PivotY})
+
+	key = f.Slices[1].Keys[0]
+	assert.False(t, key.HasCenter)
+	assert.False(t, key.HasPivot)
+}
+
+func TestNineSlicePatches(t *testing.T) {
+	key := SliceKey{X: 10, Y: 20, W: 24, H: 16, HasCenter: true, CenterX: 4, CenterY: 3, CenterW: 16, CenterH: 10}
+
+	src, dst := nineSlicePatches(key, 100, 50)
+	require.Len(t, src, 9)
+	require.Len(t, dst, 9)
+
+	assert.Equal(t, src[0], image.Rect(10, 20, 14, 23))
+	assert.Equal(t, dst[0], image.Rect(0, 0, 4, 3))
+	assert.Equal(t, src[4], image.Rect(14, 23, 30, 33))
+	assert.Equal(t, dst[4], image.Rect(4, 3, 96, 47))
+	assert.Equal(t, src[8], image.Rect(30, 33, 34, 36))
+	assert.Equal(t, dst[8], image.Rect(96, 47, 100, 50))
+
+	// Smaller than the corners: they're shrunk and the center isn't drawn.
+	_, dst = nineSlicePatches(key, 4, 6)
+	require.Len(t, dst, 4)
+	assert.Equal(t, dst[0], image.Rect(0, 0, 2, 3))
+	assert.Equal(t, dst[3], image.Rect(2, 3, 4, 6))
+
+	// Not a 9-slice: the whole slice is stretched.
+	src, dst = nineSlicePatches(SliceKey{X: 1, Y: 2, W: 3, H: 4}, 30, 40)
+	assert.Equal(t, src, []image.Rectangle{image.Rect(1, 2, 4, 6)})
+	assert.Equal(t, dst, []image.Rectangle{image.Rect(0, 0, 30, 40)})
+}
+
+func TestSliceKeyAt(t *testing.T) {
+	s := Slice{Keys: []SliceKey{{Frame: 2, X: 1}, {Frame: 5, X: 2}}}
+
+	_, ok := s.KeyAt(1)
+	assert.False(t, ok)
+
+	for frame, x := range map[int]int{2: 1, 4: 1, 5: 2, 9: 2} {
+		key, ok := s.KeyAt(frame)
+		require.True(t, ok)
+		assert.Equal(t, key.X, x, "frame %d", frame)
+	}
+
+	key, ok := s.InterpolatedKeyAt(3.5)
+	require.True(t, ok)
+	assert.Equal(t, key.X, 2)
+	assert.Equal(t, key.Frame, int32(2))
+
+	key, ok = s.InterpolatedKeyAt(7)
+	require.True(t, ok)
+	assert.Equal(t, key.X, 2)
+}
+
+func TestPlayerCurrentSlice(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteSlices))
+	require.NoError(t, err)
+
+	p := f.CreatePlayer()
+	require.NoError(t, p.Play(""))
+	p.Update(0.05)
+
+	key, ok := p.CurrentSlice("hit")
+	require.True(t, ok)
+	assert.Equal(t, []int{1, 2, 3, 4}, []int{key.X, key.Y, key.W, key.H})
+
+	p.InterpolateSlices = true
+	key, ok = p.CurrentSlice("hit")
+	require.True(t, ok)
+	assert.Equal(t, []int{3, 4, 5, 6}, []int{key.X, key.Y, key.W, key.H})
+
+	_, ok = p.CurrentSlice("missing")
+	assert.False(t, ok)
+}
@@ -0,0 +1,105 @@
+package sprite
+
+import (
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// OpenSparrowXML will use os.Open() to read the Sparrow / Starling TextureAtlas XML file path specified. Files created
+// with OpenSparrowXML() will put the XML filepath used in the Path field. duration is the duration in seconds of every
+// frame, as the format doesn't have timing.
+func OpenSparrowXML(xmlPath string, duration float32) (*File, error) {
+	r, err := os.Open(xmlPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+
+	f, err := ReadSparrowXML(r, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	f.Path = xmlPath
+	return f, nil
+}
+
+// ReadSparrowXML returns a *File for a given Sparrow / Starling TextureAtlas XML, as exported by Adobe Animate or shipped
+// with many asset packs. SubTextures are grouped into Tags by their name prefix and sorted by their trailing frame
+// number, so "run0000", "run0001" and "run0002" become the frames of the "run" Tag.
+func ReadSparrowXML(r io.Reader, duration float32) (*File, error) {
+	var atlas struct {
+		ImagePath   string `xml:"imagePath,attr"`
+		Width       int32  `xml:"width,attr"`
+		Height      int32  `xml:"height,attr"`
+		SubTextures []struct {
+			Name        string `xml:"name,attr"`
+			X           int    `xml:"x,attr"`
+			Y           int    `xml:"y,attr"`
+			Width       int    `xml:"width,attr"`
+			Height      int    `xml:"height,attr"`
+			FrameX      int    `xml:"frameX,attr"`
+			FrameY      int    `xml:"frameY,attr"`
+			FrameWidth  int    `xml:"frameWidth,attr"`
+			FrameHeight int    `xml:"frameHeight,attr"`
+			Rotated     bool   `xml:"rotated,attr"`
+		} `xml:"SubTexture"`
+	}
+
+	if err := xml.NewDecoder(r).Decode(&atlas); err != nil {
+		return nil, err
+	}
+
+	f := &File{}
+	f.ImagePath = filepath.Clean(atlas.ImagePath)
+	f.Width = atlas.Width
+	f.Height = atlas.Height
+
+	var frames []namedFrame
+	for _, st := range atlas.SubTextures {
+		frame := namedFrame{Frame: Frame{
+			Filename: st.Name,
+			X:        st.X,
+			Y:        st.Y,
+			W:        st.Width,
+			H:        st.Height,
+			Duration: duration,
+			Rotated:  st.Rotated,
+			Trimmed:  st.FrameWidth != 0 || st.FrameHeight != 0,
+			OffsetX:  -st.FrameX,
+			OffsetY:  -st.FrameY,
+			SourceW:  st.FrameWidth,
+			SourceH:  st.FrameHeight,
+		}}
+
+		// The size of a SubTexture is the area it takes on the atlas, so it's swapped for rotated ones.
+		if st.Rotated {
+			frame.W, frame.H = frame.H, frame.W
+		}
+
+		if !frame.Trimmed {
+			frame.SourceW, frame.SourceH = frame.W, frame.H
+		}
+
+		frame.tag, frame.index = splitFrameNumber(st.Name)
+		frames = append(frames, frame)
+
+		// Not every exporter writes the size of the atlas.
+		if atlas.Width == 0 && atlas.Height == 0 {
+			r := f.frameRect(frame.Frame)
+			if int32(r.Max.X) > f.Width {
+				f.Width = int32(r.Max.X)
+			}
+
+			if int32(r.Max.Y) > f.Height {
+				f.Height = int32(r.Max.Y)
+			}
+		}
+	}
+
+	f.setNamedFrames(frames)
+	return f, nil
+}
@@ -0,0 +1,47 @@
+package sprite
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSparrowXML(t *testing.T) {
+	f, err := ReadSparrowXML(strings.NewReader(sparrowXML), 0.05)
+	require.NoError(t, err)
+
+	assert.Equal(t, f.ImagePath, "character.png")
+	assert.Equal(t, f.Width, int32(130))
+	assert.Equal(t, f.Height, int32(50))
+
+	require.Len(t, f.Frames, 5)
+	assert.Equal(t, f.Frames[0].Filename, "idle0000")
+	assert.Equal(t, f.Frames[2].Filename, "run0000")
+	assert.Equal(t, f.Frames[4].Filename, "run0002")
+	assert.Equal(t, f.Frames[0].Duration, float32(0.05))
+
+	assert.Equal(t, f.Frames[3], Frame{
+		Filename: "run0001", X: 100, Y: 0, W: 40, H: 30, Duration: 0.05,
+		Rotated: true, Trimmed: true, OffsetX: 4, OffsetY: 6, SourceW: 50, SourceH: 50,
+	})
+
+	w, h := f.FrameSize(0)
+	assert.Equal(t, []int{50, 50}, []int{w, h})
+
+	require.Len(t, f.Tags, 3)
+	assert.Equal(t, f.Tags["idle"].Start, 0)
+	assert.Equal(t, f.Tags["idle"].End, 1)
+	assert.Equal(t, f.Tags["run"].Start, 2)
+	assert.Equal(t, f.Tags["run"].End, 4)
+}
+
+var sparrowXML = `<?xml version="1.0" encoding="utf-8"?>
+<TextureAtlas imagePath="character.png">
+	<SubTexture name="idle0000" x="0" y="0" width="50" height="50"/>
+	<SubTexture name="run0002" x="50" y="0" width="50" height="50" frameX="0" frameY="0" frameWidth="50" frameHeight="50"/>
+	<SubTexture name="idle0001" x="0" y="0" width="50" height="50"/>
+	<SubTexture name="run0000" x="50" y="0" width="50" height="50" frameX="0" frameY="0" frameWidth="50" frameHeight="50"/>
+	<SubTexture name="run0001" x="100" y="0" width="30" height="40" frameX="-4" frameY="-6" frameWidth="50" frameHeight="50" rotated="true"/>
+</TextureAtlas>`
@@ -40,12 +40,22 @@ func (i *spridesheetImporter) loadFile(r io.Reader) (*File, error) {
 	var count int
 	s := bufio.NewScanner(r)
 	for s.Scan() {
-		f, tag, w, h := i.parseLine(s.Text())
+		f, tag := i.parseLine(s.Text())
 		file.Frames = append(file.Frames, *f)
-		file.Width = int32(w)
-		file.Height = int32(h)
-		file.FrameWidth = int32(w)
-		file.FrameHeight = int32(h)
+
+		// The image size isn't known, so it's the area covered by the frames.
+		if w := int32(f.X + f.W); w > file.Width {
+			file.Width = w
+		}
+
+		if h := int32(f.Y + f.H); h > file.Height {
+			file.Height = h
+		}
+
+		if file.FrameWidth == 0 {
+			file.FrameWidth = int32(f.W)
+			file.FrameHeight = int32(f.H)
+		}
 
 		if tag == "" {
 			continue
@@ -80,7 +90,7 @@ func (i *spridesheetImporter) loadFile(r io.Reader) (*File, error) {
 	return file, s.Err()
 }
 
-func (i *spridesheetImporter) parseLine(line string) (*Frame, string, int, int) {
+func (i *spridesheetImporter) parseLine(line string) (*Frame, string) {
 	parts := strings.Split(line, "=")
 
 	frame := strings.TrimSpace(parts[0])
@@ -91,7 +101,6 @@ func (i *spridesheetImporter) parseLine(line string) (*Frame, string, int, int)
 		tag = strings.TrimSpace(names[0])
 	}
 
-	var w, h int
 	f := &Frame{}
 	for i, n := range strings.Split(strings.TrimSpace(parts[1]), " ") {
 		num, _ := strconv.Atoi(n)
@@ -101,12 +110,14 @@ func (i *spridesheetImporter) parseLine(line string) (*Frame, string, int, int)
 		case 1:
 			f.Y = num
 		case 2:
-			w = num
+			f.W = num
+			f.SourceW = num
 		case 3:
-			h = num
+			f.H = num
+			f.SourceH = num
 		}
 	}
 
 	f.Duration = i.duration
-	return f, tag, w, h
+	return f, tag
 }
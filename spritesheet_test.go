@@ -135,3 +135,32 @@ idle/frame0002 = 212 728 106 104
 idle/frame0003 = 318 728 106 104
 idle/frame0004 = 424 728 106 104
 idle/frame0005 = 530 728 106 104`
+
+func TestReadSpritesheetVariableSize(t *testing.T) {
+	f, err := ReadSpritesheet(strings.NewReader(dataVariableSize), "foo.txt", 0.1)
+	require.NoError(t, err)
+
+	require.Len(t, f.Frames, 3)
+	assert.Equal(t, f.FrameWidth, int32(32))
+	assert.Equal(t, f.FrameHeight, int32(32))
+	assert.Equal(t, f.Width, int32(128))
+	assert.Equal(t, f.Height, int32(48))
+
+	w, h := f.FrameSize(2)
+	assert.Equal(t, w, 48)
+	assert.Equal(t, h, 48)
+
+	p := f.CreatePlayer()
+	require.NoError(t, p.Play("charge"))
+	p.SetFrameIndex(1)
+
+	x1, y1, x2, y2 := p.CurrentFrameCoords()
+	assert.Equal(t, []int{32, 0, 72, 40}, []int{x1, y1, x2, y2})
+
+	u1, v1, u2, v2 := p.CurrentUVRect()
+	assert.Equal(t, []float64{0.25, 0, 0.5625, 40.0 / 48}, []float64{u1, v1, u2, v2})
+}
+
+var dataVariableSize = `charge/frame0000 = 0 0 32 32
+charge/frame0001 = 32 0 40 40
+charge/frame0002 = 80 0 48 48`
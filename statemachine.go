@@ -0,0 +1,406 @@
+package sprite
+
+import (
+	"errors"
+	"os"
+
+	"github.com/tidwall/gjson"
+)
+
+var (
+	ErrInvalidStateMachine = errors.New("invalid state machine")
+	ErrNoStateByName       = errors.New("no states by name")
+)
+
+// ParamType is the type of a parameter of a StateMachine.
+type ParamType int
+
+const (
+	ParamFloat   ParamType = iota // A number.
+	ParamBool                     // A boolean, compared as 1 (true) or 0 (false).
+	ParamTrigger                  // A boolean that is reset when a transition that checks it is taken.
+)
+
+// Param is a named parameter that the conditions of a StateMachine check.
+type Param struct {
+	Type    ParamType
+	Default float64 // The initial value of the parameter; 1 or 0 for booleans.
+}
+
+// StateMachine is an animation state machine: a set of States bound to Tags and the Transitions between them. It
+// only holds the definition, and can drive several Players through the Animators created from it.
+type StateMachine struct {
+	Path     string           // Path to the file; blank if the *StateMachine was loaded using Read.
+	Initial  string           // The name of the State Animators start in.
+	Params   map[string]Param // The parameters the conditions check, by name.
+	States   map[string]*State
+	AnyState []Transition // Transitions that can be taken from any State.
+}
+
+// State is a state of a StateMachine, in which the Player plays a Tag.
+type State struct {
+	Name        string
+	Tag         string      // The name of the Tag played in this State.
+	Options     PlayOptions // How the Tag is played.
+	Transitions []Transition
+}
+
+// Transition moves an Animator to another State when all its conditions are met.
+type Transition struct {
+	To         string      // The name of the State to move to.
+	Conditions []Condition // The conditions that have to be met, all of them.
+	ExitTime   float32     // The time in seconds the Animator has to stay in the State before taking the Transition.
+	WaitLoop   bool        // Whether the Transition is taken only at the end of a loop of the Tag, or once it finished.
+}
+
+// Condition compares the value of a parameter. Op is one of "==", "!=", "<", "<=", ">" or ">=" to compare it with Value,
+// "" to check that it's set (true, triggered or not 0) or "!" to check that it isn't.
+type Condition struct {
+	Param string
+	Op    string
+	Value float64
+}
+
+// OpenStateMachine will use os.ReadFile() to open the state machine JSON file path specified to parse the data. Files
+// created with OpenStateMachine() will put the JSON filepath used in the Path field.
+func OpenStateMachine(jsonPath string) (*StateMachine, error) {
+	fileData, err := os.ReadFile(jsonPath)
+	if err != nil {
+		return nil, err
+	}
+
+	sm, err := ReadStateMachine(fileData)
+	if err != nil {
+		return nil, err
+	}
+
+	sm.Path = jsonPath
+	return sm, nil
+}
+
+// ReadStateMachine returns a *StateMachine for a given sequence of bytes read from a JSON file like:
+//
+//	{
+//		"initial": "idle",
+//		"params": { "grounded": true, "speed": 0, "jump": "trigger" },
+//		"states": [
+//			{ "name": "idle", "tag": "idle", "transitions": [
+//				{ "to": "walk", "conditions": [ { "param": "speed", "op": ">", "value": 0.1 }, { "param": "grounded" } ] }
+//			] },
+//			{ "name": "walk", "tag": "walk", "speed": 1.5, "transitions": [
+//				{ "to": "idle", "waitLoop": true, "conditions": [ { "param": "speed", "op": "<=", "value": 0.1 } ] }
+//			] },
+//			{ "name": "jump", "tag": "jump", "mode": "once_hold", "transitions": [ { "to": "idle", "exitTime": 0.5 } ] }
+//		],
+//		"any": [ { "to": "jump", "conditions": [ { "param": "jump" } ] } ]
+//	}
+//
+// The type of each parameter comes from its default value, with "trigger" for triggers. A state's "mode" is one of
+// the names of the PlayModes ("loop", "once", "once_hold", "once_return" or "repeat", with "repeat": N), and "speed" the
+// speed to play its tag at, relative to the Player's PlaySpeed, which is kept as it is if there's no "speed". Conditions
+// can only check the declared parameters. The initial state is the first one if there's no "initial".
+func ReadStateMachine(data []byte) (*StateMachine, error) {
+	json := string(data)
+	if !gjson.Valid(json) {
+		return nil, ErrInvalidStateMachine
+	}
+
+	sm := &StateMachine{
+		Initial: gjson.Get(json, "initial").String(),
+		Params:  map[string]Param{},
+		States:  map[string]*State{},
+	}
+
+	var err error
+	gjson.Get(json, "params").ForEach(func(key, value gjson.Result) bool {
+		switch {
+		case value.Type == gjson.True || value.Type == gjson.False:
+			sm.Params[key.String()] = Param{Type: ParamBool, Default: boolValue(value.Bool())}
+		case value.Type == gjson.Number:
+			sm.Params[key.String()] = Param{Type: ParamFloat, Default: value.Float()}
+		case value.String() == "trigger":
+			sm.Params[key.String()] = Param{Type: ParamTrigger}
+		default:
+			err = ErrInvalidStateMachine
+		}
+
+		return err == nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stateData := range gjson.Get(json, "states").Array() {
+		state := &State{
+			Name: stateData.Get("name").String(),
+			Tag:  stateData.Get("tag").String(),
+			Options: PlayOptions{
+				Mode:   ParsePlayMode(stateData.Get("mode").String()),
+				Repeat: int(stateData.Get("repeat").Int()),
+				Speed:  float32(stateData.Get("speed").Float()),
+			},
+		}
+
+		if state.Transitions, err = readTransitions(stateData.Get("transitions"), sm.Params); err != nil {
+			return nil, err
+		}
+
+		if _, exists := sm.States[state.Name]; exists {
+			return nil, ErrInvalidStateMachine
+		}
+
+		sm.States[state.Name] = state
+		if sm.Initial == "" {
+			sm.Initial = state.Name
+		}
+	}
+
+	if sm.AnyState, err = readTransitions(gjson.Get(json, "any"), sm.Params); err != nil {
+		return nil, err
+	}
+
+	if _, ok := sm.States[sm.Initial]; !ok {
+		return nil, ErrInvalidStateMachine
+	}
+
+	for _, state := range sm.States {
+		for _, tr := range state.Transitions {
+			if _, ok := sm.States[tr.To]; !ok {
+				return nil, ErrInvalidStateMachine
+			}
+		}
+	}
+
+	for _, tr := range sm.AnyState {
+		if _, ok := sm.States[tr.To]; !ok {
+			return nil, ErrInvalidStateMachine
+		}
+	}
+
+	return sm, nil
+}
+
+// conditionOps are the operators a Condition can use.
+var conditionOps = map[string]bool{"": true, "!": true, "==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true}
+
+func readTransitions(data gjson.Result, params map[string]Param) ([]Transition, error) {
+	var transitions []Transition
+	for _, trData := range data.Array() {
+		tr := Transition{
+			To:       trData.Get("to").String(),
+			ExitTime: float32(trData.Get("exitTime").Float()),
+			WaitLoop: trData.Get("waitLoop").Bool(),
+		}
+
+		for _, condData := range trData.Get("conditions").Array() {
+			cond := Condition{
+				Param: condData.Get("param").String(),
+				Op:    condData.Get("op").String(),
+				Value: condData.Get("value").Float(),
+			}
+
+			// Booleans can be compared with true and false.
+			if value := condData.Get("value"); value.Type == gjson.True || value.Type == gjson.False {
+				cond.Value = boolValue(value.Bool())
+				if cond.Op == "" {
+					cond.Op = "=="
+				}
+			}
+
+			if _, ok := params[cond.Param]; !ok || !conditionOps[cond.Op] {
+				return nil, ErrInvalidStateMachine
+			}
+
+			tr.Conditions = append(tr.Conditions, cond)
+		}
+
+		transitions = append(transitions, tr)
+	}
+
+	return transitions, nil
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// Animator drives a Player with a StateMachine, playing the Tag of the current State and taking the Transitions whose
+// conditions are met as its parameters change.
+type Animator struct {
+	StateMachine *StateMachine
+	Player       *Player
+
+	// OnStateChange gets called when the Animator moves from a State to another.
+	OnStateChange func(a *Animator, from, to *State)
+
+	current   *State
+	stateTime float32
+	params    map[string]float64
+	loopEnded bool
+}
+
+// CreateAnimator returns a new Animator that drives the given Player with the StateMachine, starting in its initial
+// State. It returns ErrNoTagByName if a State plays a Tag that isn't in the Player's File.
+func (sm *StateMachine) CreateAnimator(p *Player) (*Animator, error) {
+	for _, state := range sm.States {
+		if _, ok := p.File.Tags[state.Tag]; !ok {
+			return nil, ErrNoTagByName
+		}
+	}
+
+	a := &Animator{
+		StateMachine: sm,
+		Player:       p,
+		params:       make(map[string]float64, len(sm.Params)),
+	}
+
+	for name, param := range sm.Params {
+		a.params[name] = param.Default
+	}
+
+	a.enter(sm.States[sm.Initial])
+	return a, nil
+}
+
+// State returns the current State.
+func (a *Animator) State() *State {
+	return a.current
+}
+
+// StateTime returns the time in seconds spent in the current State.
+func (a *Animator) StateTime() float32 {
+	return a.stateTime
+}
+
+// SetState moves to the State with the given name right away, regardless of the Transitions.
+func (a *Animator) SetState(stateName string) error {
+	state, ok := a.StateMachine.States[stateName]
+	if !ok {
+		return ErrNoStateByName
+	}
+
+	a.change(state)
+	return nil
+}
+
+// SetFloat sets the value of a number parameter.
+func (a *Animator) SetFloat(name string, value float64) {
+	a.params[name] = value
+}
+
+// SetBool sets the value of a boolean parameter.
+func (a *Animator) SetBool(name string, value bool) {
+	a.params[name] = boolValue(value)
+}
+
+// SetTrigger sets a trigger parameter, which stays set until a Transition that checks it is taken.
+func (a *Animator) SetTrigger(name string) {
+	a.params[name] = 1
+}
+
+// ResetTrigger unsets a trigger parameter.
+func (a *Animator) ResetTrigger(name string) {
+	a.params[name] = 0
+}
+
+// Float returns the value of a parameter; 1 or 0 for booleans and triggers.
+func (a *Animator) Float(name string) float64 {
+	return a.params[name]
+}
+
+// Bool returns true if a parameter is set: true, triggered or not 0.
+func (a *Animator) Bool(name string) bool {
+	return a.params[name] != 0
+}
+
+// Update updates the Player and then takes the first Transition whose conditions are met, checking the ones that can
+// be taken from any State before the ones of the current State.
+func (a *Animator) Update(dt float32) {
+	p := a.Player
+
+	loops := p.passes / p.passesPerLoop()
+	p.Update(dt)
+	a.stateTime += dt
+	a.loopEnded = p.CurrentTag == nil || p.finished || p.passes/p.passesPerLoop() > loops
+
+	for _, tr := range a.StateMachine.AnyState {
+		if tr.To != a.current.Name && a.take(tr) {
+			return
+		}
+	}
+
+	for _, tr := range a.current.Transitions {
+		if a.take(tr) {
+			return
+		}
+	}
+}
+
+// take moves to the State the Transition leads to, if it can be taken, resetting the triggers it checks.
+func (a *Animator) take(tr Transition) bool {
+	if a.stateTime < tr.ExitTime || tr.WaitLoop && !a.loopEnded {
+		return false
+	}
+
+	for _, cond := range tr.Conditions {
+		if !cond.met(a.params[cond.Param]) {
+			return false
+		}
+	}
+
+	for _, cond := range tr.Conditions {
+		if a.StateMachine.Params[cond.Param].Type == ParamTrigger {
+			a.params[cond.Param] = 0
+		}
+	}
+
+	a.change(a.StateMachine.States[tr.To])
+	return true
+}
+
+func (a *Animator) change(state *State) {
+	from := a.current
+	a.enter(state)
+
+	if a.OnStateChange != nil {
+		a.OnStateChange(a, from, state)
+	}
+}
+
+func (a *Animator) enter(state *State) {
+	a.current = state
+	a.stateTime = 0
+	a.loopEnded = false
+
+	// The tags were checked when creating the Animator.
+	_ = a.Player.Play(state.Tag, state.Options)
+}
+
+// met returns true if the value of the parameter meets the Condition.
+func (c Condition) met(value float64) bool {
+	switch c.Op {
+	case "":
+		return value != 0
+	case "!":
+		return value == 0
+	case "==":
+		return value == c.Value
+	case "!=":
+		return value != c.Value
+	case "<":
+		return value < c.Value
+	case "<=":
+		return value <= c.Value
+	case ">":
+		return value > c.Value
+	case ">=":
+		return value >= c.Value
+	}
+
+	return false
+}
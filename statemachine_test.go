@@ -0,0 +1,123 @@
+package sprite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var stateMachineJSON = `{
+	"params": { "moving": false, "speed": 0, "attack": "trigger" },
+	"states": [
+		{ "name": "idle", "tag": "reverse", "transitions": [
+			{ "to": "walk", "conditions": [ { "param": "moving", "value": true }, { "param": "speed", "op": ">", "value": 0.5 } ] }
+		] },
+		{ "name": "walk", "tag": "pingpong", "speed": 0.5, "transitions": [
+			{ "to": "idle", "waitLoop": true, "conditions": [ { "param": "moving", "op": "!" } ] }
+		] },
+		{ "name": "attack", "tag": "forward", "mode": "once_hold", "transitions": [ { "to": "idle", "exitTime": 0.25 } ] }
+	],
+	"any": [ { "to": "attack", "conditions": [ { "param": "attack" } ] } ]
+}`
+
+func TestReadStateMachine(t *testing.T) {
+	sm, err := ReadStateMachine([]byte(stateMachineJSON))
+	require.NoError(t, err)
+
+	assert.Equal(t, sm.Initial, "idle")
+	assert.Equal(t, sm.Params, map[string]Param{
+		"moving": {Type: ParamBool},
+		"speed":  {Type: ParamFloat},
+		"attack": {Type: ParamTrigger},
+	})
+
+	require.Len(t, sm.States, 3)
+	assert.Equal(t, sm.States["walk"].Options, PlayOptions{Speed: 0.5})
+	assert.Equal(t, sm.States["attack"].Options, PlayOptions{Mode: PlayModeOnceHold})
+	assert.Equal(t, sm.States["idle"].Transitions[0].Conditions, []Condition{
+		{Param: "moving", Op: "==", Value: 1},
+		{Param: "speed", Op: ">", Value: 0.5},
+	})
+	assert.True(t, sm.States["walk"].Transitions[0].WaitLoop)
+	assert.Equal(t, sm.States["attack"].Transitions[0].ExitTime, float32(0.25))
+	require.Len(t, sm.AnyState, 1)
+
+	for _, data := range []string{
+		`{ "states": [ { "name": "idle", "tag": "idle", "transitions": [ { "to": "run" } ] } ] }`,
+		`{ "initial": "run", "states": [ { "name": "idle", "tag": "idle" } ] }`,
+		`{ "params": { "a": 0 }, "states": [ { "name": "idle", "tag": "idle", "transitions": [ { "to": "idle", "conditions": [ { "param": "a", "op": "=>" } ] } ] } ] }`,
+		`{ "params": { "a": [] }, "states": [ { "name": "idle", "tag": "idle" } ] }`,
+		`{ "states": [ { "name": "idle", "tag": "idle", "transitions": [ { "to": "idle", "conditions": [ { "param": "a" } ] } ] } ] }`,
+		`{ "states": [ { "name": "idle", "tag": "idle" } ], "any": [ { "to": "idle", "conditions": [ { "param": "a" } ] } ] }`,
+		`{ "states": [] }`,
+		`{`,
+	} {
+		_, err := ReadStateMachine([]byte(data))
+		assert.ErrorIs(t, err, ErrInvalidStateMachine, data)
+	}
+}
+
+func TestAnimator(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteDirections))
+	require.NoError(t, err)
+
+	sm, err := ReadStateMachine([]byte(stateMachineJSON))
+	require.NoError(t, err)
+
+	a, err := sm.CreateAnimator(f.CreatePlayer())
+	require.NoError(t, err)
+
+	var changes []string
+	a.OnStateChange = func(a *Animator, from, to *State) { changes = append(changes, from.Name+">"+to.Name) }
+
+	assert.Equal(t, a.State().Name, "idle")
+	assert.Equal(t, a.Player.CurrentTag.Name, "reverse")
+
+	// Every condition has to be met.
+	a.SetBool("moving", true)
+	a.Update(0.1)
+	assert.Equal(t, a.State().Name, "idle")
+
+	a.SetFloat("speed", 1)
+	a.Update(0.1)
+	assert.Equal(t, a.State().Name, "walk")
+	assert.Equal(t, a.Player.CurrentTag.Name, "pingpong")
+	assert.Equal(t, a.Player.Speed(), float32(0.5))
+
+	// Going back to idle waits for the end of the ping-pong loop.
+	a.SetBool("moving", false)
+	for i := 0; i < 4; i++ {
+		a.Update(0.2)
+		assert.Equal(t, a.State().Name, "walk")
+	}
+	a.Update(0.2)
+	assert.Equal(t, a.State().Name, "idle")
+	assert.Equal(t, a.Player.Speed(), float32(1))
+
+	// The trigger is reset once the transition is taken, and the exit time is honored.
+	a.SetTrigger("attack")
+	a.Update(0.1)
+	assert.Equal(t, a.State().Name, "attack")
+	assert.False(t, a.Bool("attack"))
+	a.Update(0.1)
+	a.Update(0.1)
+	assert.Equal(t, a.State().Name, "attack")
+	a.Update(0.1)
+	assert.Equal(t, a.State().Name, "idle")
+
+	assert.Equal(t, []string{"idle>walk", "walk>idle", "idle>attack", "attack>idle"}, changes)
+
+	// States without a speed keep the Player's, even when it plays backwards.
+	a.Player.Reverse()
+	require.NoError(t, a.SetState("idle"))
+	assert.Equal(t, a.Player.Speed(), float32(-1))
+
+	assert.ErrorIs(t, a.SetState("run"), ErrNoStateByName)
+	require.NoError(t, a.SetState("walk"))
+	assert.Equal(t, a.Player.CurrentTag.Name, "pingpong")
+
+	sm.States["walk"].Tag = "run"
+	_, err = sm.CreateAnimator(f.CreatePlayer())
+	assert.ErrorIs(t, err, ErrNoTagByName)
+}
@@ -0,0 +1,176 @@
+package sprite
+
+import (
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// PlayerEvent is an event of a Player, as Listeners get it: a *LoopEvent, *FrameChangeEvent, *TagEnterEvent,
+// *TagExitEvent, *FinishEvent, *QueueNextEvent, *MarkerEvent or *DrawEvent.
+type PlayerEvent interface {
+	source() *Player
+}
+
+// LoopEvent is emitted when the playing animation / tag does a complete loop, as OnLoop.
+type LoopEvent struct {
+	Player *Player
+}
+
+// FrameChangeEvent is emitted when the playing animation / tag changes frames, as OnFrameChange.
+type FrameChangeEvent struct {
+	Player           *Player
+	Frame, PrevFrame int
+}
+
+// TagEnterEvent is emitted when entering a Tag from outside of it, as OnTagEnter.
+type TagEnterEvent struct {
+	Player *Player
+	Tag    *Tag
+}
+
+// TagExitEvent is emitted when leaving a Tag, as OnTagExit.
+type TagExitEvent struct {
+	Player *Player
+	Tag    *Tag
+}
+
+// FinishEvent is emitted when the playing animation / tag finishes, as OnFinish.
+type FinishEvent struct {
+	Player *Player
+	Tag    *Tag // The Tag that finished.
+}
+
+// QueueNextEvent is emitted when the Player starts playing the next animation / tag in its queue, as OnQueueNext.
+type QueueNextEvent struct {
+	Player *Player
+	Tag    *Tag
+}
+
+// MarkerEvent is emitted for each of the Events of a frame when the frame is shown, as OnEvent.
+type MarkerEvent struct {
+	Player *Player
+	Event  Event
+}
+
+// DrawEvent is emitted just before drawing the sprite, as OnDraw. Listeners can change the options, and set Abort to
+// skip the drawing.
+type DrawEvent struct {
+	Player  *Player // The Player being drawn, which is the outgoing one of a crossfade for the first of both draws.
+	Screen  *ebiten.Image
+	Image   *ebiten.Image
+	Options *ebiten.DrawImageOptions
+	Abort   bool
+}
+
+func (e *LoopEvent) source() *Player        { return e.Player }
+func (e *FrameChangeEvent) source() *Player { return e.Player }
+func (e *TagEnterEvent) source() *Player    { return e.Player }
+func (e *TagExitEvent) source() *Player     { return e.Player }
+func (e *FinishEvent) source() *Player      { return e.Player }
+func (e *QueueNextEvent) source() *Player   { return e.Player }
+func (e *MarkerEvent) source() *Player      { return e.Player }
+func (e *DrawEvent) source() *Player        { return e.Player }
+
+// Listener is a function that gets the events of a Player, telling them apart with a type switch.
+type Listener func(e PlayerEvent)
+
+type listener struct {
+	fn       Listener
+	priority int
+	removed  bool
+}
+
+// Subscribe adds a Listener to the events of the Player and returns a function that removes it. Listeners with a higher
+// priority get called first, and the ones with the same priority in the order they subscribed. The On* callbacks of
+// the Player act as a single Listener with priority 0 that subscribed before any other.
+//
+// Listeners can subscribe and unsubscribe while an event is being emitted: the ones that subscribe get the next
+// events, and the ones that unsubscribe don't get called anymore.
+func (p *Player) Subscribe(fn Listener, priority int) (unsubscribe func()) {
+	l := &listener{fn: fn, priority: priority}
+
+	// The list is replaced rather than modified, as emit may be going through it.
+	i := sort.Search(len(p.listeners), func(i int) bool { return p.listeners[i].priority < priority })
+	listeners := make([]*listener, 0, len(p.listeners)+1)
+	listeners = append(listeners, p.listeners[:i]...)
+	listeners = append(listeners, l)
+	p.listeners = append(listeners, p.listeners[i:]...)
+
+	return func() {
+		if l.removed {
+			return
+		}
+
+		l.removed = true
+		listeners := make([]*listener, 0, len(p.listeners)-1)
+		for _, other := range p.listeners {
+			if other != l {
+				listeners = append(listeners, other)
+			}
+		}
+		p.listeners = listeners
+	}
+}
+
+// hasListeners returns true if the Player has subscribers, besides its callbacks.
+func (p *Player) hasListeners() bool {
+	return len(p.listeners) > 0
+}
+
+// emit calls the Listeners and the callbacks of the Player with the event, in order.
+func (p *Player) emit(e PlayerEvent) {
+	callbacks := false
+	for _, l := range p.listeners {
+		if !callbacks && l.priority <= 0 {
+			p.emitCallbacks(e)
+			callbacks = true
+		}
+
+		if !l.removed {
+			l.fn(e)
+		}
+	}
+
+	if !callbacks {
+		p.emitCallbacks(e)
+	}
+}
+
+// emitCallbacks calls the On* callback of the Player for the event, if it's set.
+func (p *Player) emitCallbacks(e PlayerEvent) {
+	switch e := e.(type) {
+	case *LoopEvent:
+		if p.OnLoop != nil {
+			p.OnLoop(e.Player)
+		}
+	case *FrameChangeEvent:
+		if p.OnFrameChange != nil {
+			p.OnFrameChange(e.Player, e.Frame)
+		}
+	case *TagEnterEvent:
+		if p.OnTagEnter != nil {
+			p.OnTagEnter(e.Player, e.Tag)
+		}
+	case *TagExitEvent:
+		if p.OnTagExit != nil {
+			p.OnTagExit(e.Player, e.Tag)
+		}
+	case *FinishEvent:
+		if p.OnFinish != nil {
+			p.OnFinish(e.Player)
+		}
+	case *QueueNextEvent:
+		if p.OnQueueNext != nil {
+			p.OnQueueNext(e.Player, e.Tag)
+		}
+	case *MarkerEvent:
+		if p.OnEvent != nil {
+			p.OnEvent(e.Player, e.Event.Name, e.Event.Payload)
+		}
+	case *DrawEvent:
+		if p.OnDraw != nil && p.OnDraw(e.Player, e.Screen, e.Image, e.Options) {
+			e.Abort = true
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package sprite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlayerSubscribe(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteDirections))
+	require.NoError(t, err)
+
+	p := f.CreatePlayer()
+	require.NoError(t, p.Play("forward"))
+
+	var calls []string
+	var changes []FrameChangeEvent
+	listen := func(name string) Listener {
+		return func(e PlayerEvent) {
+			if e, ok := e.(*FrameChangeEvent); ok {
+				calls = append(calls, name)
+				changes = append(changes, *e)
+			}
+		}
+	}
+
+	p.OnFrameChange = func(p *Player, frame int) { calls = append(calls, "callback") }
+	unsubscribeA := p.Subscribe(listen("a"), 0)
+	p.Subscribe(listen("b"), 10)
+	unsubscribeC := p.Subscribe(listen("c"), -5)
+
+	// Higher priorities first, and the callbacks before the other listeners with priority 0.
+	p.Update(0.1)
+	assert.Equal(t, []string{"b", "callback", "a", "c"}, calls)
+	assert.Equal(t, changes[0], FrameChangeEvent{Player: p, Frame: 1, PrevFrame: 0})
+
+	calls = nil
+	unsubscribeA()
+	unsubscribeA()
+	p.Update(0.1)
+	assert.Equal(t, []string{"b", "callback", "c"}, calls)
+
+	// Listeners that unsubscribe while an event is being emitted don't get it.
+	calls = nil
+	p.Subscribe(func(e PlayerEvent) { unsubscribeC() }, 1)
+	p.Update(0.1)
+	assert.Equal(t, []string{"b", "callback"}, calls)
+}
+
+func TestPlayerSubscribeEvents(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteDirections))
+	require.NoError(t, err)
+
+	p := f.CreatePlayer()
+	require.NoError(t, p.Play("forward"))
+
+	var loops, finishes int
+	var finished *Tag
+	p.Subscribe(func(e PlayerEvent) {
+		switch e := e.(type) {
+		case *LoopEvent:
+			loops++
+		case *FinishEvent:
+			finishes++
+			finished = e.Tag
+		}
+	}, 0)
+
+	for i := 0; i < 6; i++ {
+		p.Update(0.1)
+	}
+
+	assert.Equal(t, loops, 1)
+	assert.Equal(t, finishes, 1)
+	assert.Equal(t, finished, f.Tags["forward"])
+
+	// Clones don't keep the subscribers.
+	assert.Empty(t, p.Clone().listeners)
+}
@@ -0,0 +1,93 @@
+package sprite
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/tidwall/gjson"
+)
+
+// OpenTexturePacker will use os.ReadFile() to open the TexturePacker JSON file path specified (either the "JSON (Hash)"
+// or the "JSON (Array)" data format) to parse the data. Files created with OpenTexturePacker() will put the JSON filepath
+// used in the Path field. duration is the duration in seconds of every frame, as TexturePacker doesn't export timing.
+// Multipack sheets are loaded as a single File, following the data files of the other pages listed in the first one.
+func OpenTexturePacker(jsonPath string, duration float32) (*File, error) {
+	fileData, err := os.ReadFile(jsonPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := [][]byte{fileData}
+	for _, related := range gjson.GetBytes(fileData, "meta.related_multi_packs").Array() {
+		pageData, err := os.ReadFile(filepath.Join(filepath.Dir(jsonPath), related.String()))
+		if err != nil {
+			return nil, err
+		}
+
+		pages = append(pages, pageData)
+	}
+
+	f, err := ReadTexturePackerPages(pages, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	f.Path = jsonPath
+	return f, nil
+}
+
+// ReadTexturePacker returns a *File for a given sequence of bytes read from a TexturePacker JSON file. Frames are
+// grouped into Tags by the folder part of their names, the same way ReadSpritesheet does with "tag/frame" names; so
+// "walk/0001.png" and "walk/0002.png" become the frames of the "walk" Tag, ordered by their trailing number. Names
+// without a folder use the name itself without its trailing number, so "run_01.png" belongs to the "run" Tag.
+func ReadTexturePacker(data []byte, duration float32) (*File, error) {
+	return ReadTexturePackerPages([][]byte{data}, duration)
+}
+
+// ReadTexturePackerPages returns a *File for the JSON files of every page of a TexturePacker multipack sheet, in order.
+// Tags can span several pages.
+func ReadTexturePackerPages(pages [][]byte, duration float32) (*File, error) {
+	f := &File{}
+
+	var frames []namedFrame
+	for _, data := range pages {
+		json := string(data)
+
+		page := f.addPage(
+			filepath.Clean(gjson.Get(json, "meta.image").String()),
+			int32(gjson.Get(json, "meta.size.w").Num),
+			int32(gjson.Get(json, "meta.size.h").Num),
+		)
+
+		add := func(name string, frameData gjson.Result) {
+			frame := namedFrame{Frame: decodeFrame(name, frameData)}
+			frame.Page = page
+			if frame.Duration == 0 {
+				frame.Duration = duration
+			}
+
+			dir, file := path.Split(name)
+			frame.tag, frame.index = splitFrameNumber(file)
+			if dir != "" {
+				frame.tag = path.Clean(dir)
+			}
+
+			frames = append(frames, frame)
+		}
+
+		if frameData := gjson.Get(json, "frames"); frameData.IsArray() {
+			for _, fd := range frameData.Array() {
+				add(fd.Get("filename").String(), fd)
+			}
+		} else {
+			frameData.ForEach(func(key, value gjson.Result) bool {
+				add(key.String(), value)
+				return true
+			})
+		}
+	}
+
+	f.setNamedFrames(frames)
+	return f, nil
+}
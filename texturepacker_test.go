@@ -0,0 +1,98 @@
+package sprite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadTexturePackerHash(t *testing.T) {
+	f, err := ReadTexturePacker([]byte(texturePackerHash), 0.1)
+	require.NoError(t, err)
+
+	assert.Equal(t, f.ImagePath, "hero.png")
+	assert.Equal(t, f.Width, int32(256))
+	assert.Equal(t, f.FrameWidth, int32(32))
+
+	require.Len(t, f.Frames, 5)
+	assert.Equal(t, f.Frames[0].Filename, "walk/0001.png")
+	assert.Equal(t, f.Frames[1].Filename, "walk/0002.png")
+	assert.Equal(t, f.Frames[2].Filename, "walk/0010.png")
+	assert.Equal(t, f.Frames[3].Filename, "jump_1.png")
+	assert.Equal(t, f.Frames[4].Filename, "jump_2.png")
+	assert.Equal(t, f.Frames[0].Duration, float32(0.1))
+	assert.Equal(t, f.Frames[0].PivotX, 0.5)
+	assert.Equal(t, f.Frames[0].PivotY, 1.0)
+	assert.True(t, f.Frames[1].Rotated)
+	assert.True(t, f.Frames[1].Trimmed)
+	assert.Equal(t, f.Frames[1].OffsetX, 2)
+
+	require.Len(t, f.Tags, 3)
+	assert.Equal(t, f.Tags["walk"].Start, 0)
+	assert.Equal(t, f.Tags["walk"].End, 2)
+	assert.Equal(t, f.Tags["jump"].Start, 3)
+	assert.Equal(t, f.Tags["jump"].End, 4)
+	assert.Equal(t, f.Tags[""].End, 4)
+}
+
+func TestReadTexturePackerArray(t *testing.T) {
+	hash, err := ReadTexturePacker([]byte(texturePackerHash), 0.1)
+	require.NoError(t, err)
+
+	array, err := ReadTexturePacker([]byte(texturePackerArray), 0.1)
+	require.NoError(t, err)
+
+	assert.Equal(t, hash.Frames, array.Frames)
+}
+
+var texturePackerHash = `{"frames": {
+"walk/0002.png": { "frame": {"x":32,"y":0,"w":28,"h":30}, "rotated": true, "trimmed": true,
+	"spriteSourceSize": {"x":2,"y":2,"w":28,"h":30}, "sourceSize": {"w":32,"h":32}, "pivot": {"x":0.5,"y":1} },
+"jump_2.png": { "frame": {"x":96,"y":0,"w":32,"h":32}, "rotated": false, "trimmed": false,
+	"spriteSourceSize": {"x":0,"y":0,"w":32,"h":32}, "sourceSize": {"w":32,"h":32}, "pivot": {"x":0.5,"y":1} },
+"walk/0001.png": { "frame": {"x":0,"y":0,"w":32,"h":32}, "rotated": false, "trimmed": false,
+	"spriteSourceSize": {"x":0,"y":0,"w":32,"h":32}, "sourceSize": {"w":32,"h":32}, "pivot": {"x":0.5,"y":1} },
+"jump_1.png": { "frame": {"x":64,"y":0,"w":32,"h":32}, "rotated": false, "trimmed": false,
+	"spriteSourceSize": {"x":0,"y":0,"w":32,"h":32}, "sourceSize": {"w":32,"h":32}, "pivot": {"x":0.5,"y":1} },
+"walk/0010.png": { "frame": {"x":128,"y":0,"w":32,"h":32}, "rotated": false, "trimmed": false,
+	"spriteSourceSize": {"x":0,"y":0,"w":32,"h":32}, "sourceSize": {"w":32,"h":32}, "pivot": {"x":0.5,"y":1} }
+},
+"meta": { "app": "https://www.codeandweb.com/texturepacker", "image": "hero.png", "format": "RGBA8888", "size": {"w":256,"h":32}, "scale": "1" }
+}`
+
+var texturePackerArray = `{"frames": [
+{ "filename": "walk/0001.png", "frame": {"x":0,"y":0,"w":32,"h":32}, "rotated": false, "trimmed": false,
+	"spriteSourceSize": {"x":0,"y":0,"w":32,"h":32}, "sourceSize": {"w":32,"h":32}, "pivot": {"x":0.5,"y":1} },
+{ "filename": "walk/0002.png", "frame": {"x":32,"y":0,"w":28,"h":30}, "rotated": true, "trimmed": true,
+	"spriteSourceSize": {"x":2,"y":2,"w":28,"h":30}, "sourceSize": {"w":32,"h":32}, "pivot": {"x":0.5,"y":1} },
+{ "filename": "walk/0010.png", "frame": {"x":128,"y":0,"w":32,"h":32}, "rotated": false, "trimmed": false,
+	"spriteSourceSize": {"x":0,"y":0,"w":32,"h":32}, "sourceSize": {"w":32,"h":32}, "pivot": {"x":0.5,"y":1} },
+{ "filename": "jump_1.png", "frame": {"x":64,"y":0,"w":32,"h":32}, "rotated": false, "trimmed": false,
+	"spriteSourceSize": {"x":0,"y":0,"w":32,"h":32}, "sourceSize": {"w":32,"h":32}, "pivot": {"x":0.5,"y":1} },
+{ "filename": "jump_2.png", "frame": {"x":96,"y":0,"w":32,"h":32}, "rotated": false, "trimmed": false,
+	"spriteSourceSize": {"x":0,"y":0,"w":32,"h":32}, "sourceSize": {"w":32,"h":32}, "pivot": {"x":0.5,"y":1} }
+],
+"meta": { "app": "https://www.codeandweb.com/texturepacker", "image": "hero.png", "format": "RGBA8888", "size": {"w":256,"h":32}, "scale": "1" }
+}`
+
+func TestReadTexturePackerPages(t *testing.T) {
+	f, err := ReadTexturePackerPages([][]byte{[]byte(texturePackerHash), []byte(texturePackerPage2)}, 0.1)
+	require.NoError(t, err)
+
+	assert.Equal(t, f.ImagePath, "hero.png")
+	assert.Equal(t, f.Pages, []Page{{"hero.png", 256, 32}, {"hero-1.png", 64, 64}})
+
+	require.Len(t, f.Frames, 6)
+	assert.Equal(t, f.Frames[3].Filename, "walk/0011.png")
+	assert.Equal(t, f.Frames[3].Page, 1)
+	assert.Equal(t, f.Frames[4].Page, 0)
+	assert.Equal(t, f.Tags["walk"].End, 3)
+}
+
+var texturePackerPage2 = `{"frames": {
+"walk/0011.png": { "frame": {"x":0,"y":0,"w":32,"h":32}, "rotated": false, "trimmed": false,
+	"spriteSourceSize": {"x":0,"y":0,"w":32,"h":32}, "sourceSize": {"w":32,"h":32}, "pivot": {"x":0.5,"y":1} }
+},
+"meta": { "app": "https://www.codeandweb.com/texturepacker", "image": "hero-1.png", "format": "RGBA8888", "size": {"w":64,"h":64}, "scale": "1" }
+}`
@@ -0,0 +1,235 @@
+package sprite
+
+import "math"
+
+// timeline is the order in which the frames of a tag are played: forward, backward or bouncing between its ends,
+// for a number of passes.
+type timeline struct {
+	tag   *Tag
+	limit int // The number of passes after which the tag finishes; 0 if it loops forever.
+}
+
+// timeline returns the timeline of the playing tag, as it's played with the Player's options.
+func (p *Player) timeline() timeline {
+	return timeline{tag: p.CurrentTag, limit: p.passLimit()}
+}
+
+// pingPong returns true if the timeline bounces at the ends of the tag.
+func (tl timeline) pingPong() bool {
+	return tl.tag.Direction.pingPong() && tl.tag.Start < tl.tag.End
+}
+
+// length returns the number of frames the whole timeline shows or, if it loops forever, the number of frames in a
+// loop. The first pass of a ping-pong shows every frame, but the next ones don't repeat the frame they bounced on.
+func (tl timeline) length() int {
+	n := tl.tag.End - tl.tag.Start + 1
+
+	switch {
+	case tl.limit == 0 && tl.pingPong():
+		return 2*n - 2
+	case tl.limit == 0:
+		return n
+	case tl.pingPong():
+		return n + (tl.limit-1)*(n-1)
+	}
+
+	return tl.limit * n
+}
+
+// at returns the frame shown at the given step of the timeline, the direction it's played in and the number of
+// passes done so far.
+func (tl timeline) at(step int) (frame, direction, passes int) {
+	t := tl.tag
+	n := t.End - t.Start + 1
+	first := 1
+	if t.Direction.reversed() {
+		first = -1
+	}
+
+	// The first pass of a ping-pong shows every frame, and the next ones start next to the frame they bounced on.
+	pos := step
+	if tl.pingPong() && step >= n {
+		passes = 1 + (step-n)/(n-1)
+		pos = 1 + (step-n)%(n-1)
+	} else if !tl.pingPong() {
+		passes, pos = step/n, step%n
+	}
+
+	direction = first
+	if passes%2 == 1 && tl.pingPong() {
+		direction = -first
+	}
+
+	if direction > 0 {
+		return t.Start + pos, direction, passes
+	}
+
+	return t.End - pos, direction, passes
+}
+
+// period returns the step from which the timeline repeats itself and the number of steps it takes to do so: every
+// pass, or every two passes after the first one for a ping-pong.
+func (tl timeline) period() (start, steps int) {
+	n := tl.tag.End - tl.tag.Start + 1
+	if tl.pingPong() {
+		return n, 2 * (n - 1)
+	}
+
+	return 0, n
+}
+
+// sum returns the time in seconds the steps of the timeline from first to last (excluded) take.
+func (tl timeline) sum(first, last int) float32 {
+	var d float32
+	for i := first; i < last; i++ {
+		frame, _, _ := tl.at(i)
+		d += tl.tag.File.Frames[frame].Duration
+	}
+
+	return d
+}
+
+// durationAt returns the time in seconds from the start of the timeline to the given step.
+func (tl timeline) durationAt(step int) float32 {
+	start, steps := tl.period()
+	if step <= start+steps {
+		return tl.sum(0, step)
+	}
+
+	cycles, rest := (step-start)/steps, (step-start)%steps
+	return tl.sum(0, start) + float32(cycles)*tl.sum(start, start+steps) + tl.sum(start, start+rest)
+}
+
+// stepAt returns the step of the timeline shown at the given time in seconds from its start, and the time since the
+// step started. The timeline has to have some duration.
+func (tl timeline) stepAt(seconds float32) (int, float32) {
+	start, steps := tl.period()
+
+	step := 0
+	if head := tl.sum(0, start); seconds >= head {
+		cycle := tl.sum(start, start+steps)
+		cycles := int((seconds - head) / cycle)
+		step = start + cycles*steps
+		seconds -= head + float32(cycles)*cycle
+	}
+
+	for ; ; step++ {
+		frame, _, _ := tl.at(step)
+		frameDur := tl.tag.File.Frames[frame].Duration
+		if seconds < frameDur {
+			return step, seconds
+		}
+
+		seconds -= frameDur
+	}
+}
+
+// duration returns the duration in seconds of the whole timeline or, if it loops forever, of a loop.
+func (tl timeline) duration() float32 {
+	return tl.durationAt(tl.length())
+}
+
+// seekStep moves the Player to the start of the given step of the timeline.
+func (p *Player) seekStep(tl timeline, step int) {
+	p.step = step
+	p.FrameIndex, p.playDirection, p.passes = tl.at(step)
+	p.frameCounter = 0
+	p.finished = false
+}
+
+// TagDuration returns the duration in seconds of the Tag with the specified name: the time it takes to play as many
+// times as its Repeat or, if it loops forever, the time a loop takes (forward and back, for a ping-pong).
+func (p *Player) TagDuration(tagName string) (float32, error) {
+	t, ok := p.File.Tags[tagName]
+	if !ok {
+		return 0, ErrNoTagByName
+	}
+
+	return timeline{tag: t, limit: t.Repeat}.duration(), nil
+}
+
+// Duration returns the duration in seconds of the playing tag as it's played: the time until it finishes or, if it
+// loops forever, the time a loop takes.
+func (p *Player) Duration() float32 {
+	if p.CurrentTag == nil {
+		return 0
+	}
+
+	return p.timeline().duration()
+}
+
+// Elapsed returns the time in seconds since the playing tag started or, if it loops forever, since its current loop
+// started.
+func (p *Player) Elapsed() float32 {
+	if p.CurrentTag == nil {
+		return 0
+	}
+
+	tl := p.timeline()
+	if p.finished {
+		return tl.duration()
+	}
+
+	return tl.durationAt(p.step%tl.length()) + p.frameCounter
+}
+
+// Progress returns how far the playing tag is, from 0 to 1: the elapsed time over its Duration.
+func (p *Player) Progress() float32 {
+	duration := p.Duration()
+	if duration <= 0 {
+		return 0
+	}
+
+	return float32(math.Min(1, float64(p.Elapsed()/duration)))
+}
+
+// TimeUntilEnd returns the time in seconds until the playing tag finishes or, if it loops forever, until the end of
+// its current loop.
+func (p *Player) TimeUntilEnd() float32 {
+	return p.Duration() - p.Elapsed()
+}
+
+// Seek moves the playing tag to the given time in seconds from its start, as returned by Elapsed. Tags that loop
+// forever wrap around, while the others are clamped between their start and their end, where they finish.
+func (p *Player) Seek(seconds float32) {
+	if p.CurrentTag == nil {
+		return
+	}
+
+	tl := p.timeline()
+	duration := tl.duration()
+	if duration <= 0 {
+		return
+	}
+
+	if tl.limit == 0 {
+		seconds = float32(math.Mod(float64(seconds), float64(duration)))
+		if seconds < 0 {
+			seconds += duration
+		}
+	} else if seconds < 0 {
+		seconds = 0
+	}
+
+	step, seconds := tl.stepAt(seconds)
+	if step >= tl.length() {
+		if tl.limit == 0 {
+			// Rounding may leave a looping tag right at the end of its loop.
+			p.seekStep(tl, 0)
+			return
+		}
+
+		p.seekStep(tl, tl.length()-1)
+		p.finish()
+		return
+	}
+
+	p.seekStep(tl, step)
+	p.frameCounter = seconds
+}
+
+// SeekNormalized moves the playing tag to the given point between its start (0) and its end (1), as returned by
+// Progress.
+func (p *Player) SeekNormalized(t float32) {
+	p.Seek(t * p.Duration())
+}
@@ -0,0 +1,154 @@
+package sprite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var asepriteDurations = `{ "frames": [
+	{ "filename": "hero 0.aseprite", "frame": { "x": 0, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 100 },
+	{ "filename": "hero 1.aseprite", "frame": { "x": 16, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 200 },
+	{ "filename": "hero 2.aseprite", "frame": { "x": 32, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 300 },
+	{ "filename": "hero 3.aseprite", "frame": { "x": 48, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 400 }
+ ],
+ "meta": {
+	"image": "hero.png", "size": { "w": 64, "h": 16 },
+	"frameTags": [
+		{ "name": "forward", "from": 0, "to": 2, "direction": "forward", "repeat": "2" },
+		{ "name": "reverse", "from": 1, "to": 3, "direction": "reverse" },
+		{ "name": "pingpong", "from": 0, "to": 2, "direction": "pingpong" },
+		{ "name": "pingpong_reverse", "from": 0, "to": 2, "direction": "pingpong_reverse", "repeat": "3" }
+	]
+ }
+}`
+
+func TestPlayerTagDuration(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteDurations))
+	require.NoError(t, err)
+
+	p := f.CreatePlayer()
+	for name, expected := range map[string]float32{"forward": 1.2, "reverse": 0.9, "pingpong": 0.8, "pingpong_reverse": 1.4, "": 1} {
+		d, err := p.TagDuration(name)
+		require.NoError(t, err)
+		assert.InDelta(t, expected, d, 1e-6, name)
+	}
+
+	_, err = p.TagDuration("missing")
+	assert.ErrorIs(t, err, ErrNoTagByName)
+}
+
+func TestPlayerSeekLooping(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteDurations))
+	require.NoError(t, err)
+
+	p := f.CreatePlayer()
+	require.NoError(t, p.Play("pingpong"))
+
+	p.Seek(0.45)
+	assert.Equal(t, p.FrameIndex, 2)
+	assert.InDelta(t, 0.45, p.Elapsed(), 1e-6)
+	assert.InDelta(t, 0.5625, p.Progress(), 1e-6)
+	assert.InDelta(t, 0.35, p.TimeUntilEnd(), 1e-6)
+
+	p.Update(0.2)
+	assert.Equal(t, p.FrameIndex, 1)
+	assert.InDelta(t, 0.65, p.Elapsed(), 1e-6)
+
+	// Looping tags wrap around.
+	p.Seek(0.85)
+	assert.Equal(t, p.FrameIndex, 0)
+	assert.InDelta(t, 0.05, p.Elapsed(), 1e-6)
+
+	// On the way back, the next frame is the previous one.
+	p.Seek(-0.1)
+	assert.Equal(t, p.FrameIndex, 1)
+	p.Update(0.15)
+	assert.Equal(t, p.FrameIndex, 0)
+
+	p.SeekNormalized(0.25)
+	assert.Equal(t, p.FrameIndex, 1)
+	assert.InDelta(t, 0.1, p.frameCounter, 1e-6)
+}
+
+func TestPlayerSeekFinite(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteDurations))
+	require.NoError(t, err)
+
+	p := f.CreatePlayer()
+	require.NoError(t, p.Play("forward"))
+	assert.InDelta(t, 1.2, p.Duration(), 1e-6)
+
+	p.SeekNormalized(0.5)
+	assert.Equal(t, p.FrameIndex, 0)
+	assert.InDelta(t, 0.6, p.Elapsed(), 1e-6)
+
+	finishes := 0
+	p.OnFinish = func(p *Player) { finishes++ }
+	p.Seek(5)
+	assert.True(t, p.IsFinished())
+	assert.Equal(t, finishes, 1)
+	assert.Equal(t, p.FrameIndex, 2)
+	assert.Equal(t, p.Progress(), float32(1))
+	assert.InDelta(t, 0, p.TimeUntilEnd(), 1e-6)
+
+	p.Seek(0.1)
+	assert.False(t, p.IsFinished())
+	assert.Equal(t, p.FrameIndex, 1)
+
+	require.NoError(t, p.Play("reverse"))
+	p.SetFrameIndex(1)
+	assert.Equal(t, p.FrameIndex, 2)
+	assert.InDelta(t, 0.4, p.Elapsed(), 1e-6)
+}
+
+func TestTimelineAt(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteDurations))
+	require.NoError(t, err)
+
+	// Walk every timeline frame by frame, as the Player does.
+	for name, tag := range f.Tags {
+		tl := timeline{tag: tag}
+		frame, direction, passes := tl.at(0)
+		var elapsed float32
+		for step := 0; step < 50; step++ {
+			f, d, n := tl.at(step)
+			assert.Equal(t, []int{f, d, n}, []int{frame, direction, passes}, "%s at %d", name, step)
+			assert.InDelta(t, elapsed, tl.durationAt(step), 1e-4, "%s at %d", name, step)
+
+			elapsed += tag.File.Frames[frame].Duration
+			if next := frame + direction; next >= tag.Start && next <= tag.End {
+				frame = next
+				continue
+			}
+
+			passes++
+			if tl.pingPong() {
+				direction = -direction
+				frame += direction
+			} else if direction > 0 {
+				frame = tag.Start
+			} else {
+				frame = tag.End
+			}
+		}
+	}
+}
+
+func TestPlayerSeekLongTimeline(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteDurations))
+	require.NoError(t, err)
+
+	p := f.CreatePlayer()
+	require.NoError(t, p.Play("pingpong", PlayOptions{Mode: PlayModeRepeat, Repeat: 500000}))
+
+	// The first pass takes 0.6s, and every loop after it 0.8s: 0.3s back and 0.5s forward, as the frames it bounces on
+	// aren't repeated. The last loop only goes back.
+	assert.InDelta(t, 400000.1, p.Duration(), 0.1)
+
+	// 0.25s into a loop is the first frame, on the way back.
+	p.Seek(200000.05)
+	assert.Equal(t, p.FrameIndex, 0)
+	assert.InDelta(t, 200000.05, p.Elapsed(), 0.1)
+}
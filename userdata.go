@@ -0,0 +1,108 @@
+package sprite
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// UserData is the user data text of a tag, layer, cel or slice, as set in Aseprite. Besides free text, it can hold a
+// JSON object or a list of key=value pairs, which can be read with its methods. Pairs are separated by commas,
+// semicolons or whitespace, and a key without a value is a flag ("boss, hp=300, speed=1.5").
+type UserData string
+
+// IsJSON returns true if the user data is a JSON object.
+func (d UserData) IsJSON() bool {
+	s := strings.TrimSpace(string(d))
+	return strings.HasPrefix(s, "{") && gjson.Valid(s)
+}
+
+// Values returns the top-level values of the user data by key, as text. Flags have empty values, so the words of free
+// text come back as flags too.
+func (d UserData) Values() map[string]string {
+	values := map[string]string{}
+
+	if d.IsJSON() {
+		gjson.Parse(string(d)).ForEach(func(key, value gjson.Result) bool {
+			values[key.String()] = value.String()
+			return true
+		})
+
+		return values
+	}
+
+	for _, pair := range d.pairs() {
+		values[pair[0]] = pair[1]
+	}
+
+	return values
+}
+
+// Get returns the value of the given key in the user data, and whether it's set.
+func (d UserData) Get(key string) (string, bool) {
+	value, ok := d.Values()[key]
+	return value, ok
+}
+
+// Decode stores the user data in the value pointed to by v, as json.Unmarshal does. key=value pairs are decoded as a
+// JSON object, with values that look like numbers or booleans as such, and flags as true.
+func (d UserData) Decode(v interface{}) error {
+	if d.IsJSON() {
+		return json.Unmarshal([]byte(d), v)
+	}
+
+	object := map[string]interface{}{}
+	for _, pair := range d.pairs() {
+		key, value := pair[0], pair[1]
+		if !strings.Contains(pair[2], "=") {
+			object[key] = true
+		} else if n, err := strconv.ParseFloat(value, 64); err == nil {
+			object[key] = n
+		} else if b, err := strconv.ParseBool(value); err == nil {
+			object[key] = b
+		} else {
+			object[key] = value
+		}
+	}
+
+	data, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// pairs returns the key, the value and the original text of each key=value pair in the user data.
+func (d UserData) pairs() [][3]string {
+	fields := strings.FieldsFunc(string(d), func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+
+	pairs := make([][3]string, 0, len(fields))
+	for _, field := range fields {
+		key, value := field, ""
+		if i := strings.Index(field, "="); i >= 0 {
+			key, value = field[:i], field[i+1:]
+		}
+
+		if key != "" {
+			pairs = append(pairs, [3]string{key, value, field})
+		}
+	}
+
+	return pairs
+}
+
+// parseColor returns a color as written in the Aseprite JSON export ("#rrggbbaa") as a 0xRRGGBBAA number, or 0 if it's
+// empty or invalid.
+func parseColor(s string) int64 {
+	color, err := strconv.ParseInt(strings.TrimPrefix(s, "#"), 16, 64)
+	if err != nil {
+		return 0
+	}
+
+	return color
+}
@@ -0,0 +1,159 @@
+package sprite
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var asepriteUserData = `{ "frames": {
+	"hero 0.aseprite": { "frame": { "x": 0, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 100 },
+	"hero 1.aseprite": { "frame": { "x": 16, "y": 0, "w": 16, "h": 16 }, "sourceSize": { "w": 16, "h": 16 }, "duration": 100 }
+ },
+ "meta": {
+	"image": "hero.png", "size": { "w": 32, "h": 16 },
+	"frameTags": [
+		{ "name": "attack", "from": 0, "to": 1, "direction": "forward", "color": "#fe5b59ff", "data": "{\"damage\": 12, \"element\": \"fire\"}" }
+	],
+	"layers": [
+		{ "name": "Body", "opacity": 255, "blendMode": "normal", "color": "#0000ff80", "data": "skin",
+			"cels": [ { "frame": 1, "color": "#00ff00ff", "data": "event=footstep" } ] }
+	],
+	"slices": [
+		{ "name": "empty", "color": "", "keys": [ { "frame": 0, "bounds": { "x": 0, "y": 0, "w": 1, "h": 1 } } ] },
+		{ "name": "spawn", "color": "#ff000080", "data": "projectile=arrow, speed=120", "keys": [] }
+	]
+ }
+}`
+
+func TestReadAsepriteUserData(t *testing.T) {
+	f, err := ReadAseprite([]byte(asepriteUserData))
+	require.NoError(t, err)
+
+	tag := f.Tags["attack"]
+	assert.Equal(t, tag.Color, int64(0xfe5b59ff))
+	assert.True(t, tag.Data.IsJSON())
+	assert.Equal(t, f.Tags[""].Data, UserData(""))
+
+	require.Len(t, f.Layers, 1)
+	assert.Equal(t, f.Layers[0].Data, UserData("skin"))
+	assert.Equal(t, f.Layers[0].Color, int64(0x0000ff80))
+
+	_, ok := f.Layers[0].CelAt(0)
+	assert.False(t, ok)
+	cel, ok := f.Layers[0].CelAt(1)
+	require.True(t, ok)
+	assert.Equal(t, cel, Cel{Frame: 1, Data: "event=footstep", Color: 0x00ff00ff})
+
+	require.Len(t, f.Slices, 2)
+	assert.Equal(t, f.Slices[0].Color, int64(0))
+	assert.Equal(t, f.Slices[1].Color, int64(0xff000080))
+}
+
+func TestUserDataValues(t *testing.T) {
+	d := UserData("boss; hp=300, speed=1.5\nname=ogre")
+	assert.False(t, d.IsJSON())
+	assert.Equal(t, d.Values(), map[string]string{"boss": "", "hp": "300", "speed": "1.5", "name": "ogre"})
+
+	value, ok := d.Get("hp")
+	assert.True(t, ok)
+	assert.Equal(t, value, "300")
+
+	_, ok = d.Get("missing")
+	assert.False(t, ok)
+
+	d = UserData(`{"damage": 12, "element": "fire", "tags": ["a"]}`)
+	assert.True(t, d.IsJSON())
+	assert.Equal(t, d.Values(), map[string]string{"damage": "12", "element": "fire", "tags": `["a"]`})
+
+	assert.Empty(t, UserData("").Values())
+
+	// Free text is read as flags.
+	d = UserData("boss fight")
+	assert.Equal(t, d.Values(), map[string]string{"boss": "", "fight": ""})
+	_, ok = d.Get("boss")
+	assert.True(t, ok)
+}
+
+func TestUserDataDecode(t *testing.T) {
+	var attack struct {
+		Damage  int
+		Element string
+		Heavy   bool
+	}
+
+	require.NoError(t, UserData(`{"damage": 12, "element": "fire"}`).Decode(&attack))
+	assert.Equal(t, attack.Damage, 12)
+	assert.Equal(t, attack.Element, "fire")
+
+	require.NoError(t, UserData("damage=30 element=ice heavy").Decode(&attack))
+	assert.Equal(t, attack.Damage, 30)
+	assert.Equal(t, attack.Element, "ice")
+	assert.True(t, attack.Heavy)
+
+	var values map[string]interface{}
+	require.NoError(t, UserData("loop=false, speed=0.5").Decode(&values))
+	assert.Equal(t, values, map[string]interface{}{"loop": false, "speed": 0.5})
+
+	assert.Error(t, UserData("damage=high").Decode(&attack))
+}
+
+// aseChunk returns a chunk of an .aseprite file with the given fields, written in order.
+func aseChunk(kind uint16, fields ...interface{}) []byte {
+	data := &bytes.Buffer{}
+	for _, field := range fields {
+		if s, ok := field.(string); ok {
+			binary.Write(data, binary.LittleEndian, uint16(len(s)))
+			data.WriteString(s)
+			continue
+		}
+
+		binary.Write(data, binary.LittleEndian, field)
+	}
+
+	chunk := &bytes.Buffer{}
+	binary.Write(chunk, binary.LittleEndian, uint32(data.Len()+6))
+	binary.Write(chunk, binary.LittleEndian, kind)
+	chunk.Write(data.Bytes())
+	return chunk.Bytes()
+}
+
+func TestReadAsepriteBinaryUserData(t *testing.T) {
+	chunks := [][]byte{
+		// A layer, with a cel followed by its extra data and then its user data.
+		aseChunk(aseChunkLayer, [6]uint16{aseLayerVisible}, uint8(255), [3]uint8{}, "body"),
+		aseChunk(aseChunkCel, uint16(0), [2]int16{}, uint8(255), uint16(aseCelRaw), int16(0), [5]uint8{},
+			[2]uint16{1, 1}, [4]uint8{255, 0, 0, 255}),
+		aseChunk(aseChunkCelExtra, uint32(1), [4]int32{}, [16]uint8{}),
+		aseChunk(aseChunkUserData, uint32(aseUserDataText), "event=footstep"),
+
+		// A tag with a color, and user data with text only.
+		aseChunk(aseChunkTags, uint16(1), [8]uint8{}, [2]uint16{0, 0}, uint8(0), uint16(0), [6]uint8{},
+			[3]uint8{0x12, 0x34, 0x56}, uint8(0), "walk"),
+		aseChunk(aseChunkUserData, uint32(aseUserDataText), "boss"),
+	}
+
+	file := &bytes.Buffer{}
+	require.NoError(t, binary.Write(file, binary.LittleEndian, aseHeader{
+		Magic: aseFileMagic, Frames: 1, Width: 1, Height: 1, Depth: 32, Flags: 1,
+	}))
+	require.NoError(t, binary.Write(file, binary.LittleEndian, aseFrameHeader{
+		Magic: aseFrameMagic, Duration: 100, Chunks: uint32(len(chunks)),
+	}))
+	for _, chunk := range chunks {
+		file.Write(chunk)
+	}
+
+	f, _, err := ReadAsepriteBinary(file)
+	require.NoError(t, err)
+
+	require.Len(t, f.Layers, 1)
+	assert.Equal(t, f.Layers[0].Cels, []Cel{{Frame: 0, Data: "event=footstep"}})
+	assert.Equal(t, f.Frames[0].Events, []Event{{Name: "footstep", Payload: "event=footstep"}})
+
+	assert.Equal(t, f.Tags["walk"].Data, UserData("boss"))
+	assert.Equal(t, f.Tags["walk"].Color, int64(0x123456ff))
+}